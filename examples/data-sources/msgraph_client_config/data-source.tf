@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    msgraph = {
+      source = "Microsoft/msgraph"
+    }
+  }
+}
+
+provider "msgraph" {
+}
+
+data "msgraph_client_config" "current" {
+}
+
+// the current principal is the owner of the application
+resource "msgraph_resource" "application" {
+  url = "applications"
+  body = {
+    displayName         = "My Application"
+    "owners@odata.bind" = [
+      "https://graph.microsoft.com/v1.0/directoryObjects/${data.msgraph_client_config.current.object_id}",
+    ]
+  }
+
+  lifecycle {
+    precondition {
+      condition     = contains(data.msgraph_client_config.current.roles, "Application.ReadWrite.All") || contains(data.msgraph_client_config.current.scopes, "Application.ReadWrite.All")
+      error_message = "The provider must be granted the Application.ReadWrite.All permission."
+    }
+  }
+}
+
+output "credential" {
+  // it will output the authentication method which was used, e.g. "azure_cli"
+  value = data.msgraph_client_config.current.credential
+}
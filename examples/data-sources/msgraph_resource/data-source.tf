@@ -31,3 +31,19 @@ output "all" {
   // it will output the whole response
   value = data.msgraph_resource.application.output.all
 }
+
+// advanced queries, e.g. `$count=true` or `endsWith` in `$filter`, are sent with the `ConsistencyLevel: eventual` header
+data "msgraph_resource" "guest_users" {
+  url = "users"
+  query_parameters = {
+    "$count"  = ["true"]
+    "$filter" = ["endsWith(mail,'@contoso.com')"]
+  }
+  response_export_values = {
+    count = "\"@odata.count\""
+  }
+}
+
+output "guest_users_count" {
+  value = data.msgraph_resource.guest_users.output.count
+}
@@ -0,0 +1,37 @@
+terraform {
+  required_providers {
+    msgraph = {
+      source = "Microsoft/msgraph"
+    }
+  }
+}
+
+provider "msgraph" {
+}
+
+variable "delta_token" {
+  type        = string
+  default     = null
+  description = "The delta token returned by the previous run, it's null in the initial run."
+}
+
+data "msgraph_resource_delta" "users" {
+  url         = "users/delta"
+  delta_token = var.delta_token
+  query_parameters = {
+    "$select" = ["displayName", "userPrincipalName"]
+  }
+  response_export_values = {
+    changes = "value"
+  }
+}
+
+output "changes" {
+  // it will output the users which were added, updated or removed since the previous run
+  value = data.msgraph_resource_delta.users.output.changes
+}
+
+output "next_delta_token" {
+  // it will output the delta token which should be used in the next run
+  value = data.msgraph_resource_delta.users.next_delta_token
+}
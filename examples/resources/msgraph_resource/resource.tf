@@ -33,3 +33,97 @@ output "resource_url" {
   // it will output something like "applications/12345678-1234-1234-1234-123456789abc"
   value = msgraph_resource.application.resource_url
 }
+
+// the password is read via its application and removed via the removePassword action
+resource "msgraph_resource" "password" {
+  url = "applications/${msgraph_resource.application.id}/addPassword"
+  body = {
+    passwordCredential = {
+      displayName = "My Password"
+    }
+  }
+  read_url      = "applications/${msgraph_resource.application.id}"
+  delete_url    = "applications/${msgraph_resource.application.id}/removePassword"
+  delete_method = "POST"
+  delete_body = {
+    keyId = "{response.keyId}"
+  }
+}
+
+// a rerun after an interrupted create adopts the application with the same uniqueName instead of creating a duplicate
+resource "msgraph_resource" "upserted_application" {
+  url        = "applications"
+  upsert_key = "uniqueName"
+  body = {
+    uniqueName  = "my-unique-application"
+    displayName = "My Upserted Application"
+  }
+}
+
+// the group is only returned to the resources which depend on it after its mail address is populated
+resource "msgraph_resource" "mail_enabled_group" {
+  url = "groups"
+  body = {
+    displayName     = "My Unified Group"
+    mailEnabled     = true
+    mailNickname    = "my-unified-group"
+    securityEnabled = false
+    groupTypes      = ["Unified"]
+  }
+  wait_for = {
+    expression = "mail != null"
+    interval   = "10s"
+    timeout    = "5m"
+  }
+}
+
+// a replacement of the group doesn't conflict with its mailNickname, because the deletion waits until the group is gone
+resource "msgraph_resource" "security_group" {
+  url = "groups"
+  body = {
+    displayName     = "My Security Group"
+    mailEnabled     = false
+    mailNickname    = "my-security-group"
+    securityEnabled = true
+  }
+  wait_for_deletion = {
+    interval = "5s"
+  }
+}
+
+// the group is permanently deleted when it's destroyed, and a deleted group with the same mailNickname is restored instead of creating a new one
+resource "msgraph_resource" "purged_group" {
+  url = "groups"
+  body = {
+    displayName     = "My Purged Group"
+    mailEnabled     = false
+    mailNickname    = "my-purged-group"
+    securityEnabled = true
+  }
+  delete_behavior         = "purge"
+  restore_if_soft_deleted = true
+}
+
+// the password is sent when the user is created or the password changes, but it's never stored in the plan or state
+resource "msgraph_resource" "user" {
+  url = "users"
+  body = {
+    accountEnabled    = true
+    displayName       = "My User"
+    mailNickname      = "my-user"
+    userPrincipalName = "my-user@contoso.onmicrosoft.com"
+    passwordProfile = {
+      forceChangePasswordNextSignIn = true
+    }
+  }
+  sensitive_body = {
+    passwordProfile = {
+      password = var.user_password
+    }
+  }
+}
+
+variable "user_password" {
+  type      = string
+  sensitive = true
+}
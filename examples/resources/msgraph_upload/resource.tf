@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    msgraph = {
+      source = "Microsoft/msgraph"
+    }
+  }
+}
+
+provider "msgraph" {}
+
+variable "drive_id" {
+  type = string
+}
+
+// upload a large file to the root folder of a drive, the file is uploaded again when its content changes
+resource "msgraph_upload" "report" {
+  url    = "drives/${var.drive_id}/items/root:/report.pdf:/createUploadSession"
+  source = "${path.module}/report.pdf"
+  body = {
+    item = {
+      "@microsoft.graph.conflictBehavior" = "replace"
+    }
+  }
+  chunk_size = 5242880 // 5 MiB
+  response_export_values = {
+    web_url  = "webUrl"
+    xor_hash = "file.hashes.quickXorHash"
+  }
+}
+
+output "web_url" {
+  value = msgraph_upload.report.output.web_url
+}
@@ -0,0 +1,368 @@
+package acceptance
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+// The recording mode is sourced from the `MSGRAPH_TEST_RECORDING_MODE` environment variable:
+//   - live: the tests send the requests to Microsoft Graph, this is the default.
+//   - record: the tests send the requests to Microsoft Graph, and the interactions are saved as a cassette per test.
+//   - replay: the responses are served from the cassette of the test, no tenant or credential is required.
+const (
+	RecordingModeLive   = "live"
+	RecordingModeRecord = "record"
+	RecordingModeReplay = "replay"
+
+	recordingModeEnv      = "MSGRAPH_TEST_RECORDING_MODE"
+	recordingsRequiredEnv = "MSGRAPH_TEST_RECORDINGS_REQUIRED"
+	recordingsDirEnv      = "MSGRAPH_TEST_RECORDINGS_DIR"
+	defaultRecordingsDir  = "testdata/recordings"
+)
+
+// cassetteRedactor redacts the credentials and the sensitive properties from the recorded interactions.
+var cassetteRedactor = clients.NewRedactor(nil, nil)
+
+// RecordingMode returns the recording mode of the acceptance tests.
+func RecordingMode() string {
+	switch v := strings.ToLower(os.Getenv(recordingModeEnv)); v {
+	case RecordingModeRecord, RecordingModeReplay:
+		return v
+	default:
+		return RecordingModeLive
+	}
+}
+
+// RecordingsRequired returns whether a test fails rather than skips in replay mode when its recording doesn't exist.
+// It's sourced from the `MSGRAPH_TEST_RECORDINGS_REQUIRED` environment variable, and defaults to true in CI, where the
+// `CI` environment variable is set, so that a missing recording doesn't go unnoticed.
+func RecordingsRequired() bool {
+	if v := os.Getenv(recordingsRequiredEnv); v != "" {
+		required, _ := strconv.ParseBool(v)
+		return required
+	}
+	return os.Getenv("CI") != ""
+}
+
+// Cassette contains the interactions of a test, and the random test data used to build its configurations,
+// so that the replayed requests are identical to the recorded ones.
+type Cassette struct {
+	RandomInteger int               `json:"randomInteger"`
+	RandomString  string            `json:"randomString"`
+	Interactions  []clients.Traffic `json:"interactions"`
+}
+
+// liveTransport sends the requests to Microsoft Graph in record mode. It has the settings of the default transport of
+// the client, e.g. the proxy from the environment variables and TLS 1.2 or later, so the recorded traffic is the same as in live mode.
+var liveTransport policy.Transporter = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig: &tls.Config{
+			MinVersion:    tls.VersionTLS12,
+			Renegotiation: tls.RenegotiateFreelyAsClient,
+		},
+	},
+}
+
+// recorder is a transport which records the interactions with Microsoft Graph, or replays them from a cassette.
+type recorder struct {
+	mode string
+	path string
+	// transport sends the requests in record mode
+	transport policy.Transporter
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+var _ policy.Transporter = &recorder{}
+
+func cassettePath(testName string) string {
+	dir := defaultRecordingsDir
+	if v := os.Getenv(recordingsDirEnv); v != "" {
+		dir = v
+	}
+	return filepath.Join(dir, strings.ReplaceAll(testName, "/", "_")+".json")
+}
+
+// newRecorder returns a recorder of the mode, the cassette is loaded from the path in replay mode.
+func newRecorder(mode string, path string) (*recorder, error) {
+	r := &recorder{
+		mode:      mode,
+		path:      path,
+		transport: liveTransport,
+	}
+	if mode != RecordingModeReplay {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse the recording %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// save writes the recorded interactions to the cassette.
+func (r *recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o600)
+}
+
+// credential returns the credential used in replay mode, no token is requested from Microsoft Entra ID.
+// In other modes, it returns nil, so the credential is built from the environment variables.
+func (r *recorder) credential() azcore.TokenCredential {
+	if r.mode == RecordingModeReplay {
+		return replayCredential{}
+	}
+	return nil
+}
+
+func (r *recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == RecordingModeReplay {
+		if resp := r.replay(req, body); resp != nil {
+			return resp, nil
+		}
+		return nil, &replayError{fmt.Errorf("no unused interaction in the recording %s matches %s %s", r.path, req.Method, req.URL.String())}
+	}
+	return r.record(req, body)
+}
+
+func (r *recorder) record(req *http.Request, body string) (*http.Response, error) {
+	resp, err := r.transport.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, clients.Traffic{
+		LiveRequest: clients.LiveRequest{
+			Headers: cassetteRedactor.RedactHeaders(req.Header),
+			Method:  req.Method,
+			Url:     req.URL.String(),
+			Body:    cassetteRedactor.RedactBody(body),
+		},
+		LiveResponse: clients.LiveResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    cassetteRedactor.RedactHeaders(resp.Header),
+			Body:       cassetteRedactor.RedactBody(string(responseBody)),
+		},
+	})
+	return resp, nil
+}
+
+// replay returns the response of the first unused interaction which matches the method, the normalised URL and the body of the request,
+// or nil if there's none. The identical requests, e.g. polling a resource until it's ready, are answered in the recorded order.
+func (r *recorder) replay(req *http.Request, body string) *http.Response {
+	key := matchKey(req.Method, req.URL.String(), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || matchKey(interaction.LiveRequest.Method, interaction.LiveRequest.Url, interaction.LiveRequest.Body) != key {
+			continue
+		}
+		r.used[i] = true
+
+		header := http.Header{}
+		for k, v := range interaction.LiveResponse.Headers {
+			header.Set(k, v)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.LiveResponse.StatusCode, http.StatusText(interaction.LiveResponse.StatusCode)),
+			StatusCode:    interaction.LiveResponse.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.LiveResponse.Body)),
+			ContentLength: int64(len(interaction.LiveResponse.Body)),
+			Request:       req,
+		}
+	}
+	return nil
+}
+
+// references returns whether the path of the request contains an ID which appeared in the recorded interactions.
+func (r *recorder) references(req *http.Request) bool {
+	ids := uuidRegex.FindAllString(req.URL.Path, -1)
+	if len(ids) == 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, interaction := range r.cassette.Interactions {
+		for _, id := range ids {
+			if strings.Contains(interaction.LiveRequest.Url, id) || strings.Contains(interaction.LiveResponse.Body, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var uuidRegex = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+var (
+	activeRecorders     = make(map[*recorder]bool)
+	activeRecordersLock = &sync.Mutex{}
+)
+
+func registerRecorder(r *recorder) {
+	activeRecordersLock.Lock()
+	defer activeRecordersLock.Unlock()
+	activeRecorders[r] = true
+}
+
+func unregisterRecorder(r *recorder) {
+	activeRecordersLock.Lock()
+	defer activeRecordersLock.Unlock()
+	delete(activeRecorders, r)
+}
+
+// sharedTransport is the transport of the client returned by BuildTestClient in record and replay modes. The client is
+// shared by the test cases, e.g. it's used by `check.That(...).Exists`, so each request is routed to the recorder of the
+// test case which it belongs to: in replay mode, the one which has a matching interaction; in record mode, the one
+// which has seen the IDs in the request path.
+type sharedTransport struct {
+	mode string
+}
+
+func (t sharedTransport) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	activeRecordersLock.Lock()
+	recorders := make([]*recorder, 0, len(activeRecorders))
+	for r := range activeRecorders {
+		recorders = append(recorders, r)
+	}
+	activeRecordersLock.Unlock()
+
+	if t.mode == RecordingModeReplay {
+		for _, r := range recorders {
+			if resp := r.replay(req, body); resp != nil {
+				return resp, nil
+			}
+		}
+		return nil, &replayError{fmt.Errorf("no unused interaction in the active recordings matches %s %s", req.Method, req.URL.String())}
+	}
+
+	for _, r := range recorders {
+		if r.references(req) {
+			return r.record(req, body)
+		}
+	}
+	return liveTransport.Do(req)
+}
+
+// matchKey returns the key used to match a request against the recorded ones. The host is ignored so the recordings can
+// be replayed against any cloud, the query parameters are sorted, and JSON bodies are compared regardless of the formatting.
+// The sensitive properties are redacted before comparing, because they're redacted in the recordings.
+func matchKey(method string, rawUrl string, body string) string {
+	body = cassetteRedactor.RedactBody(body)
+	normalisedUrl := rawUrl
+	if u, err := url.Parse(rawUrl); err == nil {
+		normalisedUrl = strings.TrimSuffix(u.Path, "/")
+		if query := u.Query().Encode(); query != "" {
+			normalisedUrl += "?" + query
+		}
+	}
+
+	normalisedBody := strings.TrimSpace(body)
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err == nil {
+		if data, err := json.Marshal(v); err == nil {
+			normalisedBody = string(data)
+		}
+	}
+	return strings.ToUpper(method) + " " + normalisedUrl + " " + normalisedBody
+}
+
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// replayError is returned when no recorded interaction matches the request, it's not retried by the client.
+type replayError struct {
+	error
+}
+
+func (e *replayError) NonRetriable() {}
+
+func (e *replayError) Unwrap() error {
+	return e.error
+}
+
+type replayCredential struct{}
+
+func (replayCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "replay", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
@@ -0,0 +1,247 @@
+package acceptance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const testObjectId = "00000000-0000-0000-0000-000000000001"
+
+func TestMatchKey(t *testing.T) {
+	testcases := []struct {
+		Name    string
+		A       [3]string
+		B       [3]string
+		Matches bool
+	}{
+		{
+			Name:    "different hosts",
+			A:       [3]string{"GET", "https://graph.microsoft.com/v1.0/users", ""},
+			B:       [3]string{"GET", "https://graph.microsoft.us/v1.0/users/", ""},
+			Matches: true,
+		},
+		{
+			Name:    "query parameters in different order",
+			A:       [3]string{"GET", "https://graph.microsoft.com/v1.0/users?$select=id&$top=1", ""},
+			B:       [3]string{"get", "https://graph.microsoft.com/v1.0/users?$top=1&$select=id", ""},
+			Matches: true,
+		},
+		{
+			Name:    "json bodies in different formats",
+			A:       [3]string{"POST", "https://graph.microsoft.com/v1.0/users", `{"a":1,"b":[1,2]}`},
+			B:       [3]string{"POST", "https://graph.microsoft.com/v1.0/users", "{\n  \"b\": [1, 2],\n  \"a\": 1\n}"},
+			Matches: true,
+		},
+		{
+			Name:    "different bodies",
+			A:       [3]string{"POST", "https://graph.microsoft.com/v1.0/users", `{"a":1}`},
+			B:       [3]string{"POST", "https://graph.microsoft.com/v1.0/users", `{"a":2}`},
+			Matches: false,
+		},
+		{
+			Name:    "different sensitive properties",
+			A:       [3]string{"POST", "https://graph.microsoft.com/v1.0/users", `{"passwordProfile":{"password":"a"}}`},
+			B:       [3]string{"POST", "https://graph.microsoft.com/v1.0/users", `{"passwordProfile":{"password":"b"}}`},
+			Matches: true,
+		},
+		{
+			Name:    "different methods",
+			A:       [3]string{"PATCH", "https://graph.microsoft.com/v1.0/users", ""},
+			B:       [3]string{"DELETE", "https://graph.microsoft.com/v1.0/users", ""},
+			Matches: false,
+		},
+		{
+			Name:    "different query parameters",
+			A:       [3]string{"GET", "https://graph.microsoft.com/v1.0/users?$top=1", ""},
+			B:       [3]string{"GET", "https://graph.microsoft.com/v1.0/users?$top=2", ""},
+			Matches: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			a := matchKey(tc.A[0], tc.A[1], tc.A[2])
+			b := matchKey(tc.B[0], tc.B[1], tc.B[2])
+			if (a == b) != tc.Matches {
+				t.Fatalf("expected matches to be %v, got %q and %q", tc.Matches, a, b)
+			}
+		})
+	}
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&count, 1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"id":%q,"request":%s}`, testObjectId, body)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"id":%q,"count":%d}`, testObjectId, n)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "TestRecorder.json")
+	r, err := newRecorder(RecordingModeRecord, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.cassette.RandomString = "abcde"
+
+	send := func(transport policy.Transporter, method string, url string, body string) (int, string, error) {
+		req, _ := http.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := transport.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data), nil
+	}
+
+	itemUrl := fmt.Sprintf("%s/v1.0/applications/%s?$select=id&$top=1", server.URL, testObjectId)
+	if _, _, err := send(r, http.MethodPost, server.URL+"/v1.0/applications", `{"b":1,"a":2}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := send(r, http.MethodGet, itemUrl, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := r.save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Fatalf("the recording must not contain the Authorization header: %s", data)
+	}
+
+	r, err = newRecorder(RecordingModeReplay, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.cassette.RandomString != "abcde" || len(r.cassette.Interactions) != 3 {
+		t.Fatalf("unexpected cassette: %+v", r.cassette)
+	}
+
+	statusCode, body, err := send(r, http.MethodPost, "https://graph.microsoft.com/v1.0/applications", `{"a":2,"b":1}`)
+	if err != nil || statusCode != http.StatusCreated || !strings.Contains(body, `"request":{"b":1,"a":2}`) {
+		t.Fatalf("unexpected response: %d %s %v", statusCode, body, err)
+	}
+	replayUrl := fmt.Sprintf("https://graph.microsoft.com/v1.0/applications/%s?$top=1&$select=id", testObjectId)
+	for i := 2; i <= 3; i++ {
+		statusCode, body, err = send(r, http.MethodGet, replayUrl, "")
+		if err != nil || statusCode != http.StatusOK || !strings.Contains(body, fmt.Sprintf(`"count":%d`, i)) {
+			t.Fatalf("unexpected response: %d %s %v", statusCode, body, err)
+		}
+	}
+
+	_, _, err = send(r, http.MethodGet, replayUrl, "")
+	var replayErr *replayError
+	if !errors.As(err, &replayErr) {
+		t.Fatalf("expected a replay error, got %v", err)
+	}
+}
+
+func TestSharedTransport_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TestSharedTransport.json")
+	data := fmt.Sprintf(`{"interactions":[{"request":{"method":"GET","url":"https://graph.microsoft.com/v1.0/applications/%s"},"response":{"statusCode":404,"body":"{}"}}]}`, testObjectId)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := newRecorder(RecordingModeReplay, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registerRecorder(r)
+	defer unregisterRecorder(r)
+
+	transport := sharedTransport{mode: RecordingModeReplay}
+	req, _ := http.NewRequest(http.MethodGet, "https://graph.microsoft.com/v1.0/applications/"+testObjectId, nil)
+	resp, err := transport.Do(req)
+	if err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected response: %v %v", resp, err)
+	}
+	if !r.used[0] {
+		t.Fatalf("expected the interaction to be used")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://graph.microsoft.com/v1.0/applications/"+testObjectId, nil)
+	if _, err := transport.Do(req); err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+}
+
+type transporterFunc func(req *http.Request) (*http.Response, error)
+
+func (f transporterFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRecorder_RecordsWithTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"id":%q}`, testObjectId)
+	}))
+	defer server.Close()
+
+	r, err := newRecorder(RecordingModeRecord, filepath.Join(t.TempDir(), "TestRecorder.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.transport != liveTransport {
+		t.Fatalf("expected the recorder to send the requests with the live transport")
+	}
+	var requests int32
+	r.transport = transporterFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return liveTransport.Do(req)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1.0/applications/"+testObjectId, nil)
+	resp, err := r.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response: %v %v", resp, err)
+	}
+	if requests != 1 || len(r.cassette.Interactions) != 1 {
+		t.Fatalf("expected the request to be sent with the transport of the recorder and recorded, got %d requests and %d interactions", requests, len(r.cassette.Interactions))
+	}
+}
+
+func TestRecordingsRequired(t *testing.T) {
+	testcases := []struct {
+		Required string
+		CI       string
+		Expected bool
+	}{
+		{Required: "", CI: "", Expected: false},
+		{Required: "", CI: "true", Expected: true},
+		{Required: "true", CI: "", Expected: true},
+		{Required: "false", CI: "true", Expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Setenv(recordingsRequiredEnv, tc.Required)
+		t.Setenv("CI", tc.CI)
+		if actual := RecordingsRequired(); actual != tc.Expected {
+			t.Errorf("expected %v for %s=%q and CI=%q, got %v", tc.Expected, recordingsRequiredEnv, tc.Required, tc.CI, actual)
+		}
+	}
+}
@@ -35,11 +35,14 @@ type TestData struct {
 
 	// resourceLabel is the local used for the resource - generally "test""
 	resourceLabel string
+
+	// recorder records or replays the traffic of this test case, it's nil in live mode
+	recorder *recorder
 }
 
 // BuildTestData generates some test data for the given resource
 func BuildTestData(t *testing.T, resourceType string, resourceLabel string) TestData {
-	return TestData{
+	td := TestData{
 		RandomInteger: RandTimeInt(),
 		RandomString:  RandStringFromCharSet(5, charSetAlphaNum),
 		ResourceName:  fmt.Sprintf("%s.%s", resourceType, resourceLabel),
@@ -47,6 +50,51 @@ func BuildTestData(t *testing.T, resourceType string, resourceLabel string) Test
 		ResourceType:  resourceType,
 		resourceLabel: resourceLabel,
 	}
+
+	switch mode := RecordingMode(); mode {
+	case RecordingModeRecord:
+		path := cassettePath(t.Name())
+		r, err := newRecorder(mode, path)
+		if err != nil {
+			t.Fatalf("failed to create the recorder: %v", err)
+		}
+		r.cassette.RandomInteger = td.RandomInteger
+		r.cassette.RandomString = td.RandomString
+		registerRecorder(r)
+		t.Cleanup(func() {
+			unregisterRecorder(r)
+			if t.Failed() {
+				t.Logf("the test failed, skip saving the recording %s", path)
+				return
+			}
+			if err := r.save(); err != nil {
+				t.Errorf("failed to save the recording %s: %v", path, err)
+			}
+		})
+		td.recorder = r
+	case RecordingModeReplay:
+		path := cassettePath(t.Name())
+		r, err := newRecorder(mode, path)
+		if os.IsNotExist(err) {
+			if RecordingsRequired() {
+				t.Fatalf("the recording %s doesn't exist, record it with `make testacc-record`", path)
+			}
+			t.Skipf("skipping, the recording %s doesn't exist", path)
+		}
+		if err != nil {
+			t.Fatalf("failed to load the recording: %v", err)
+		}
+		// the configurations must be identical to the recorded ones
+		td.RandomInteger = r.cassette.RandomInteger
+		td.RandomString = r.cassette.RandomString
+		registerRecorder(r)
+		t.Cleanup(func() {
+			unregisterRecorder(r)
+		})
+		td.recorder = r
+	}
+
+	return td
 }
 
 // RandomIntOfLength is a random 8 to 18 digit integer which is unique to this test case
@@ -151,8 +199,13 @@ func (td TestData) RunAcceptanceTest(t *testing.T, testCase resource.TestCase) {
 }
 
 func (td TestData) providers() map[string]func() (tfprotov6.ProviderServer, error) {
+	p := &provider.MSGraphProvider{}
+	if td.recorder != nil {
+		p.Transport = td.recorder
+		p.Credential = td.recorder.credential()
+	}
 	return map[string]func() (tfprotov6.ProviderServer, error){
-		"msgraph": providerserver.NewProtocol6WithError(&provider.MSGraphProvider{}),
+		"msgraph": providerserver.NewProtocol6WithError(p),
 	}
 }
 
@@ -176,6 +229,9 @@ For tests that authenticate with Azure by using a Service Principal with Certifi
 - ARM_CLIENT_ID
 - ARM_CLIENT_CERTIFICATE_PATH
 - ARM_TENANT_ID
+
+For tests that replay the recorded traffic without a tenant, the following environment variable must be set:
+- MSGRAPH_TEST_RECORDING_MODE=replay
 `)
 	}
 }
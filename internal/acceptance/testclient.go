@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"sync"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
@@ -24,110 +24,129 @@ func BuildTestClient() (*clients.Client, error) {
 	defer clientLock.Unlock()
 
 	if _client == nil {
-		var cloudConfig cloud.Configuration
-		env := os.Getenv("ARM_ENVIRONMENT")
-		switch strings.ToLower(env) {
-		case "public":
-			cloudConfig = cloud.AzurePublic
-		case "usgovernment":
-			cloudConfig = cloud.AzureGovernment
-		case "china":
-			cloudConfig = cloud.AzureChina
-		default:
-			cloudConfig = cloud.AzurePublic
+		var cred azcore.TokenCredential
+		var transport policy.Transporter
+		switch mode := RecordingMode(); mode {
+		case RecordingModeRecord:
+			transport = sharedTransport{mode: mode}
+		case RecordingModeReplay:
+			cred = replayCredential{}
+			transport = sharedTransport{mode: mode}
 		}
 
-		model := provider.MSGraphProviderModel{}
-
-		// set the defaults from environment variables
-		if v := os.Getenv("ARM_CLIENT_ID"); v != "" {
-			model.ClientID = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_CLIENT_ID_FILE_PATH"); v != "" {
-			model.ClientIDFilePath = types.StringValue(v)
+		client, err := buildTestClient(cred, transport)
+		if err != nil {
+			return nil, err
 		}
+		_client = client
+	}
 
-		if v := os.Getenv("ARM_USE_AKS_WORKLOAD_IDENTITY"); v != "" {
-			model.UseAKSWorkloadIdentity = types.BoolValue(v == "true")
-		} else {
-			model.UseAKSWorkloadIdentity = types.BoolValue(false)
-		}
-		if v := os.Getenv("ARM_TENANT_ID"); v != "" {
-			model.TenantID = types.StringValue(v)
-		}
-		if model.UseAKSWorkloadIdentity.ValueBool() && os.Getenv("AZURE_TENANT_ID") != "" {
-			aksTenantID := os.Getenv("AZURE_TENANT_ID")
-			if model.TenantID.ValueString() != "" && model.TenantID.ValueString() != aksTenantID {
-				return nil, fmt.Errorf("invalid `tenant_id` value: mismatch between supplied Tenant ID and that provided by AKS Workload Identity - please remove, ensure they match, or disable use_aks_workload_identity")
-			}
-			model.TenantID = types.StringValue(aksTenantID)
-		}
+	return _client, nil
+}
 
-		if v := os.Getenv("ARM_CLIENT_CERTIFICATE"); v != "" {
-			model.ClientCertificate = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_CLIENT_CERTIFICATE_PATH"); v != "" {
-			model.ClientCertificatePath = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_CLIENT_CERTIFICATE_PASSWORD"); v != "" {
-			model.ClientCertificatePassword = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_CLIENT_SECRET"); v != "" {
-			model.ClientSecret = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_CLIENT_SECRET_FILE_PATH"); v != "" {
-			model.ClientSecretFilePath = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_OIDC_REQUEST_TOKEN"); v != "" {
-			model.OIDCRequestToken = types.StringValue(v)
-		} else if v := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"); v != "" {
-			model.OIDCRequestToken = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_OIDC_REQUEST_URL"); v != "" {
-			model.OIDCRequestURL = types.StringValue(v)
-		} else if v := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"); v != "" {
-			model.OIDCRequestURL = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_OIDC_TOKEN"); v != "" {
-			model.OIDCToken = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_OIDC_TOKEN_FILE_PATH"); v != "" {
-			model.OIDCTokenFilePath = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_OIDC_AZURE_SERVICE_CONNECTION_ID"); v != "" {
-			model.OIDCAzureServiceConnectionID = types.StringValue(v)
-		}
-		if v := os.Getenv("ARM_USE_OIDC"); v != "" {
-			model.UseOIDC = types.BoolValue(v == "true")
-		} else {
-			model.UseOIDC = types.BoolValue(false)
-		}
-		if v := os.Getenv("ARM_USE_CLI"); v != "" {
-			model.UseCLI = types.BoolValue(v == "true")
-		} else {
-			model.UseCLI = types.BoolValue(true)
-		}
+// buildTestClient builds a client whose credential is sourced from the environment variables, unless cred is specified.
+// The transport, if specified, overrides the HTTP transport of the client.
+func buildTestClient(cred azcore.TokenCredential, transport policy.Transporter) (*clients.Client, error) {
+	cloudConfig, err := clients.NewCloudConfiguration(os.Getenv("ARM_ENVIRONMENT"), os.Getenv("ARM_GRAPH_ENDPOINT"), os.Getenv("ARM_AUTHORITY_HOST"))
+	if err != nil {
+		return nil, err
+	}
+
+	model := provider.MSGraphProviderModel{}
+
+	// set the defaults from environment variables
+	if v := os.Getenv("ARM_CLIENT_ID"); v != "" {
+		model.ClientID = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_CLIENT_ID_FILE_PATH"); v != "" {
+		model.ClientIDFilePath = types.StringValue(v)
+	}
 
-		option := azidentity.DefaultAzureCredentialOptions{
-			TenantID: model.TenantID.ValueString(),
+	if v := os.Getenv("ARM_USE_AKS_WORKLOAD_IDENTITY"); v != "" {
+		model.UseAKSWorkloadIdentity = types.BoolValue(v == "true")
+	} else {
+		model.UseAKSWorkloadIdentity = types.BoolValue(false)
+	}
+	if v := os.Getenv("ARM_TENANT_ID"); v != "" {
+		model.TenantID = types.StringValue(v)
+	}
+	if model.UseAKSWorkloadIdentity.ValueBool() && os.Getenv("AZURE_TENANT_ID") != "" {
+		aksTenantID := os.Getenv("AZURE_TENANT_ID")
+		if model.TenantID.ValueString() != "" && model.TenantID.ValueString() != aksTenantID {
+			return nil, fmt.Errorf("invalid `tenant_id` value: mismatch between supplied Tenant ID and that provided by AKS Workload Identity - please remove, ensure they match, or disable use_aks_workload_identity")
 		}
-		cred, err := provider.BuildChainedTokenCredential(model, option)
+		model.TenantID = types.StringValue(aksTenantID)
+	}
+
+	if v := os.Getenv("ARM_CLIENT_CERTIFICATE"); v != "" {
+		model.ClientCertificate = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_CLIENT_CERTIFICATE_PATH"); v != "" {
+		model.ClientCertificatePath = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_CLIENT_CERTIFICATE_PASSWORD"); v != "" {
+		model.ClientCertificatePassword = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_CLIENT_SECRET"); v != "" {
+		model.ClientSecret = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_CLIENT_SECRET_FILE_PATH"); v != "" {
+		model.ClientSecretFilePath = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_OIDC_REQUEST_TOKEN"); v != "" {
+		model.OIDCRequestToken = types.StringValue(v)
+	} else if v := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"); v != "" {
+		model.OIDCRequestToken = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_OIDC_REQUEST_URL"); v != "" {
+		model.OIDCRequestURL = types.StringValue(v)
+	} else if v := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"); v != "" {
+		model.OIDCRequestURL = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_OIDC_TOKEN"); v != "" {
+		model.OIDCToken = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_OIDC_TOKEN_FILE_PATH"); v != "" {
+		model.OIDCTokenFilePath = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_OIDC_AZURE_SERVICE_CONNECTION_ID"); v != "" {
+		model.OIDCAzureServiceConnectionID = types.StringValue(v)
+	}
+	if v := os.Getenv("ARM_USE_OIDC"); v != "" {
+		model.UseOIDC = types.BoolValue(v == "true")
+	} else {
+		model.UseOIDC = types.BoolValue(false)
+	}
+	if v := os.Getenv("ARM_USE_CLI"); v != "" {
+		model.UseCLI = types.BoolValue(v == "true")
+	} else {
+		model.UseCLI = types.BoolValue(true)
+	}
+
+	option := azidentity.DefaultAzureCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud: cloudConfig,
+		},
+		TenantID: model.TenantID.ValueString(),
+	}
+	if cred == nil {
+		chainedCred, err := provider.BuildChainedTokenCredential(model, option)
 		if err != nil {
 			return nil, fmt.Errorf("failed to obtain a credential: %v", err)
 		}
+		cred = chainedCred
+	}
 
-		copt := &clients.Option{
-			Cred:     cred,
-			CloudCfg: cloudConfig,
-			TenantId: os.Getenv("ARM_TENANT_ID"),
-		}
-
-		client := &clients.Client{}
-		if err := client.Build(context.TODO(), copt); err != nil {
-			return nil, err
-		}
-		_client = client
+	copt := &clients.Option{
+		Cred:      cred,
+		CloudCfg:  cloudConfig,
+		TenantId:  os.Getenv("ARM_TENANT_ID"),
+		Transport: transport,
 	}
 
-	return _client, nil
+	client := &clients.Client{}
+	if err := client.Build(context.TODO(), copt); err != nil {
+		return nil, err
+	}
+	return client, nil
 }
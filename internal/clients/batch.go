@@ -0,0 +1,304 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	// MaxBatchSize is the maximum number of sub-requests which can be sent in a single JSON batch request.
+	MaxBatchSize = 20
+
+	defaultBatchMaxRetries    = 3
+	defaultBatchRetryDelay    = time.Second
+	defaultBatchMaxRetryDelay = 60 * time.Second
+)
+
+// BatchRequest is a sub-request of a JSON batch request. The Url is relative to the API version, for example
+// `/groups/{id}/members/$ref`.
+type BatchRequest struct {
+	Method  string
+	Url     string
+	Headers map[string]string
+	Body    interface{}
+}
+
+// BatchResponse is the response of a sub-request of a JSON batch request.
+type BatchResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       interface{}
+}
+
+// BatchError is returned when some sub-requests of a JSON batch request failed after all retries.
+type BatchError struct {
+	Failures []BatchFailure
+}
+
+// BatchFailure describes a sub-request which failed, Err is the *azcore.ResponseError built from its response.
+type BatchFailure struct {
+	Request  BatchRequest
+	Response BatchResponse
+	Err      error
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, failure.Err.Error())
+	}
+	return fmt.Sprintf("%d of the batch requests failed:\n%s", len(e.Failures), strings.Join(messages, "\n"))
+}
+
+type batchRequestItem struct {
+	Id      string            `json:"id"`
+	Method  string            `json:"method"`
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+type batchResponseItem struct {
+	Id      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    interface{}       `json:"body"`
+}
+
+// Batch sends the requests using JSON batching, up to MaxBatchSize sub-requests are packed into a single `$batch`
+// request. The sub-requests which failed with a retryable status code are retried, honouring their Retry-After
+// header and the maximum attempts of the retry options. The responses are returned in the same order as the requests,
+// and a *BatchError is returned if any of the sub-requests failed.
+func (client *MSGraphClient) Batch(ctx context.Context, apiVersion string, requests []BatchRequest, options RequestOptions) ([]BatchResponse, error) {
+	if options.RetryOptions != nil {
+		ctx = withRetryOptions(ctx, options.RetryOptions)
+	}
+
+	responses := make([]BatchResponse, len(requests))
+	for start := 0; start < len(requests); start += MaxBatchSize {
+		end := start + MaxBatchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		if err := client.batchWithRetry(ctx, apiVersion, requests[start:end], responses[start:end], options); err != nil {
+			return nil, err
+		}
+	}
+
+	failures := make([]BatchFailure, 0)
+	for i, resp := range responses {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err := runtime.NewResponseError(client.batchHttpResponse(ctx, apiVersion, requests[i], resp))
+			failures = append(failures, BatchFailure{Request: requests[i], Response: resp, Err: err})
+		}
+	}
+	if len(failures) != 0 {
+		return responses, &BatchError{Failures: failures}
+	}
+	return responses, nil
+}
+
+func (client *MSGraphClient) batchWithRetry(ctx context.Context, apiVersion string, requests []BatchRequest, responses []BatchResponse, options RequestOptions) error {
+	maxRetries := int32(defaultBatchMaxRetries)
+	retryDelay := defaultBatchRetryDelay
+	maxRetryDelay := defaultBatchMaxRetryDelay
+	if opt := options.RetryOptions; opt != nil {
+		// the options which retry until the context deadline keep the default retries of the sub-requests, so a sub-request
+		// which keeps failing doesn't hold the operation until its timeout
+		switch {
+		case opt.MaxRetries < 0:
+			maxRetries = 0
+		case opt.MaxRetries > 0 && opt.MaxRetries < unboundedMaxRetries:
+			maxRetries = opt.MaxRetries
+		}
+		if opt.RetryDelay > 0 {
+			retryDelay = opt.RetryDelay
+		}
+		if opt.MaxRetryDelay > 0 {
+			maxRetryDelay = opt.MaxRetryDelay
+		}
+	}
+
+	pending := make([]int, len(requests))
+	states := make([]*retryState, len(requests))
+	for i := range requests {
+		pending[i] = i
+		states[i] = &retryState{}
+	}
+	for attempt := int32(0); ; attempt++ {
+		items := make([]BatchRequest, 0, len(pending))
+		for _, i := range pending {
+			items = append(items, requests[i])
+			atomic.AddInt32(&states[i].attempts, 1)
+		}
+		results, err := client.batch(ctx, apiVersion, items)
+		if err != nil {
+			return err
+		}
+
+		retryable := make([]int, 0)
+		delay := time.Duration(0)
+		for j, i := range pending {
+			responses[i] = results[j]
+			if !client.shouldRetryBatchResponse(context.WithValue(ctx, retryStateKey{}, states[i]), apiVersion, requests[i], results[j], options) {
+				continue
+			}
+			// the maximum attempts of the retry options take precedence over the default retries
+			limit := maxRetries
+			if n := atomic.LoadInt32(&states[i].maxAttempts); n > 0 {
+				limit = n - 1
+			}
+			if attempt >= limit {
+				continue
+			}
+			retryable = append(retryable, i)
+			// the delay of the Retry-After header is honoured, otherwise the delay of the backoff of the retry options is used
+			d := parseRetryAfter(&http.Response{Header: batchResponseHeader(results[j])})
+			if d == 0 {
+				d = time.Duration(atomic.SwapInt64(&states[i].delay, 0))
+			}
+			if d > delay {
+				delay = d
+			}
+		}
+		if len(retryable) == 0 {
+			return nil
+		}
+
+		if delay == 0 {
+			delay = retryDelay * time.Duration(1<<attempt)
+			if delay > maxRetryDelay {
+				delay = maxRetryDelay
+			}
+		}
+		log.Printf("[DEBUG] Retrying %d of %d batch requests in %s", len(retryable), len(pending), delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		pending = retryable
+	}
+}
+
+// batch sends a single `$batch` request which contains at most MaxBatchSize sub-requests.
+func (client *MSGraphClient) batch(ctx context.Context, apiVersion string, requests []BatchRequest) ([]BatchResponse, error) {
+	items := make([]batchRequestItem, 0, len(requests))
+	for i, request := range requests {
+		item := batchRequestItem{
+			Id:      strconv.Itoa(i),
+			Method:  request.Method,
+			Url:     "/" + strings.TrimPrefix(request.Url, "/"),
+			Headers: make(map[string]string),
+			Body:    request.Body,
+		}
+		for key, value := range request.Headers {
+			item.Headers[key] = value
+		}
+		// the Content-Type header is required for the sub-requests which have a body
+		if _, ok := item.Headers["Content-Type"]; !ok && item.Body != nil {
+			item.Headers["Content-Type"] = "application/json"
+		}
+		items = append(items, item)
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(client.host, apiVersion, "/$batch"))
+	if err != nil {
+		return nil, err
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	if err := runtime.MarshalAsJSON(req, map[string]interface{}{"requests": items}); err != nil {
+		return nil, err
+	}
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, runtime.NewResponseError(resp)
+	}
+
+	var responseBody struct {
+		Responses []batchResponseItem `json:"responses"`
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+		return nil, err
+	}
+
+	out := make([]BatchResponse, len(requests))
+	for _, item := range responseBody.Responses {
+		i, err := strconv.Atoi(item.Id)
+		if err != nil || i < 0 || i >= len(requests) {
+			return nil, fmt.Errorf("unexpected batch response id %q", item.Id)
+		}
+		out[i] = BatchResponse{
+			StatusCode: item.Status,
+			Headers:    item.Headers,
+			Body:       item.Body,
+		}
+	}
+	for i, item := range out {
+		if item.StatusCode == 0 {
+			return nil, fmt.Errorf("missing batch response for %s %s", requests[i].Method, requests[i].Url)
+		}
+	}
+	return out, nil
+}
+
+func batchResponseHeader(resp BatchResponse) http.Header {
+	header := http.Header{}
+	for key, value := range resp.Headers {
+		header.Set(key, value)
+	}
+	return header
+}
+
+// shouldRetryBatchResponse checks whether a sub-request should be retried, the same rules as the ones of the
+// pipeline's retry policy are applied. The context carries the retry state of the sub-request.
+func (client *MSGraphClient) shouldRetryBatchResponse(ctx context.Context, apiVersion string, req BatchRequest, resp BatchResponse, options RequestOptions) bool {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false
+	}
+	if options.RetryOptions != nil && options.RetryOptions.ShouldRetry != nil {
+		return options.RetryOptions.ShouldRetry(client.batchHttpResponse(ctx, apiVersion, req, resp), nil)
+	}
+	statusCodes := DefaultRetryableStatusCodes
+	if options.RetryOptions != nil && len(options.RetryOptions.StatusCodes) != 0 {
+		statusCodes = options.RetryOptions.StatusCodes
+	}
+	for _, code := range statusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// batchHttpResponse converts the sub-response to an *http.Response, so it can be handled like any other response.
+func (client *MSGraphClient) batchHttpResponse(ctx context.Context, apiVersion string, req BatchRequest, resp BatchResponse) *http.Response {
+	body := make([]byte, 0)
+	if resp.Body != nil {
+		if data, err := json.Marshal(resp.Body); err == nil {
+			body = data
+		}
+	}
+	rawRequest, _ := http.NewRequestWithContext(ctx, req.Method, runtime.JoinPaths(client.host, apiVersion, req.Url), nil)
+	return &http.Response{
+		StatusCode: resp.StatusCode,
+		Status:     fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		Header:     batchResponseHeader(resp),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    rawRequest,
+	}
+}
@@ -0,0 +1,159 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+func TestBatch(t *testing.T) {
+	var mu sync.Mutex
+	batchSizes := make([]int, 0)
+	attempts := make(map[string]int)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/$batch" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Requests []batchRequestItem `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		batchSizes = append(batchSizes, len(body.Requests))
+		responses := make([]batchResponseItem, 0)
+		for _, item := range body.Requests {
+			attempts[item.Url]++
+			switch {
+			case item.Url == "/users/3" && attempts[item.Url] == 1:
+				responses = append(responses, batchResponseItem{Id: item.Id, Status: http.StatusTooManyRequests, Headers: map[string]string{"Retry-After-Ms": "1"}})
+			case item.Url == "/users/5":
+				responses = append(responses, batchResponseItem{Id: item.Id, Status: http.StatusNotFound, Body: map[string]interface{}{"error": map[string]interface{}{"code": "Request_ResourceNotFound", "message": "not found"}}})
+			default:
+				if item.Method == http.MethodPost && item.Headers["Content-Type"] != "application/json" {
+					t.Errorf("missing content type for %s", item.Url)
+				}
+				responses = append(responses, batchResponseItem{Id: item.Id, Status: http.StatusOK, Body: map[string]interface{}{"url": item.Url}})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+
+	requests := make([]BatchRequest, 0)
+	for i := 0; i < 45; i++ {
+		requests = append(requests, BatchRequest{Method: http.MethodPost, Url: fmt.Sprintf("users/%d", i), Body: map[string]interface{}{}})
+	}
+
+	responses, err := client.Batch(context.Background(), "v1.0", requests, RequestOptions{})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a batch error, got %v", err)
+	}
+	if len(batchErr.Failures) != 1 || batchErr.Failures[0].Request.Url != "users/5" || !strings.Contains(err.Error(), "Request_ResourceNotFound") {
+		t.Fatalf("unexpected batch error: %v", err)
+	}
+
+	if len(responses) != len(requests) {
+		t.Fatalf("got %d responses, want %d", len(responses), len(requests))
+	}
+	for i, resp := range responses {
+		if i == 5 {
+			continue
+		}
+		if resp.StatusCode != http.StatusOK || resp.Body.(map[string]interface{})["url"] != fmt.Sprintf("/users/%d", i) {
+			t.Fatalf("unexpected response %d: %+v", i, resp)
+		}
+	}
+
+	// 3 batches of 20, 20 and 5 requests, and a retry of the throttled request
+	if fmt.Sprint(batchSizes) != "[20 1 20 5]" {
+		t.Fatalf("unexpected batch sizes: %v", batchSizes)
+	}
+	if attempts["/users/3"] != 2 || attempts["/users/5"] != 1 {
+		t.Fatalf("unexpected attempts: %v", attempts)
+	}
+}
+
+func TestBatch_MaxRetries(t *testing.T) {
+	testcases := []struct {
+		name    string
+		options *policy.RetryOptions
+		want    int
+	}{
+		{name: "default", options: nil, want: defaultBatchMaxRetries + 1},
+		{name: "until the timeout", options: newRetryOptions(nil, nil, ""), want: defaultBatchMaxRetries + 1},
+		{name: "explicit", options: &policy.RetryOptions{MaxRetries: 1}, want: 2},
+		{name: "disabled", options: &policy.RetryOptions{MaxRetries: -1}, want: 1},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			attempts := 0
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Requests []batchRequestItem `json:"requests"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				mu.Lock()
+				attempts++
+				mu.Unlock()
+				responses := []batchResponseItem{{Id: body.Requests[0].Id, Status: http.StatusServiceUnavailable, Headers: map[string]string{"Retry-After-Ms": "1"}}}
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+			}))
+
+			_, err := client.Batch(context.Background(), "v1.0", []BatchRequest{{Method: http.MethodGet, Url: "users/1"}}, RequestOptions{RetryOptions: tc.options})
+			var batchErr *BatchError
+			if !errors.As(err, &batchErr) {
+				t.Fatalf("expected a batch error, got %v", err)
+			}
+			if attempts != tc.want {
+				t.Errorf("attempts: got %d, want %d", attempts, tc.want)
+			}
+		})
+	}
+}
+
+func TestBatch_MaxAttempts(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []batchRequestItem `json:"requests"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		responses := []batchResponseItem{{Id: body.Requests[0].Id, Status: http.StatusServiceUnavailable}}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+
+	b := retryBackoff{MaxAttempts: 6, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	options := newRetryOptions(nil, nil, "")
+	options.ShouldRetry = b.wrap(options.ShouldRetry)
+	withBackoffDelay(options)
+	// the options which retry until the timeout don't lift the maximum attempts
+	options = CombineRetryOptions(options, NewRetryOptionsForReadAfterCreate())
+
+	_, err := client.Batch(context.Background(), "v1.0", []BatchRequest{{Method: http.MethodGet, Url: "users/1"}}, RequestOptions{RetryOptions: options})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a batch error, got %v", err)
+	}
+	if attempts != b.MaxAttempts {
+		t.Errorf("attempts: got %d, want %d", attempts, b.MaxAttempts)
+	}
+}
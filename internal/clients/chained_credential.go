@@ -0,0 +1,68 @@
+package clients
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// NamedCredential is a credential of a chain, the name describes how it authenticates, e.g. `azure_cli`.
+type NamedCredential struct {
+	Name       string
+	Credential azcore.TokenCredential
+}
+
+// ChainedCredential tries the credentials in order like azidentity.ChainedTokenCredential, and records the name of the
+// credential which issued the last token, so the provider can report how it's authenticated.
+type ChainedCredential struct {
+	chain *azidentity.ChainedTokenCredential
+
+	mu   sync.Mutex
+	name string
+}
+
+var _ azcore.TokenCredential = &ChainedCredential{}
+
+func NewChainedCredential(sources []NamedCredential) (*ChainedCredential, error) {
+	c := &ChainedCredential{}
+	creds := make([]azcore.TokenCredential, 0, len(sources))
+	for _, source := range sources {
+		creds = append(creds, &recordingCredential{name: source.Name, cred: source.Credential, chain: c})
+	}
+	chain, err := azidentity.NewChainedTokenCredential(creds, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.chain = chain
+	return c, nil
+}
+
+func (c *ChainedCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return c.chain.GetToken(ctx, options)
+}
+
+// Name returns the name of the credential which issued the last token, it's empty if no token has been issued yet.
+func (c *ChainedCredential) Name() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.name
+}
+
+type recordingCredential struct {
+	name  string
+	cred  azcore.TokenCredential
+	chain *ChainedCredential
+}
+
+func (c *recordingCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	token, err := c.cred.GetToken(ctx, options)
+	if err == nil {
+		c.chain.mu.Lock()
+		c.chain.name = c.name
+		c.chain.mu.Unlock()
+	}
+	return token, err
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	azlog "github.com/Azure/azure-sdk-for-go/sdk/azcore/log"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
 )
 
 type Client struct {
@@ -18,6 +19,9 @@ type Client struct {
 	MSGraphClient *MSGraphClient
 
 	Option *Option
+
+	// scheduler is shared by all requests to handle throttling and cap the concurrency
+	scheduler *throttlingScheduler
 }
 
 type Option struct {
@@ -27,6 +31,23 @@ type Option struct {
 	CloudCfg                    cloud.Configuration
 	CustomCorrelationRequestID  string
 	TenantId                    string
+	MaxConcurrentRequests       int
+	// RedactedPaths and RedactedHeaders are redacted from the logged traffic in addition to the built-in sensitive ones
+	RedactedPaths   []string
+	RedactedHeaders []string
+	// Transport overrides the HTTP transport, it's used by the acceptance tests to record and replay the traffic.
+	Transport policy.Transporter
+	Tracing   TracingOptions
+	Defaults  Defaults
+}
+
+// LogThrottlingSummary logs the throttling statistics of the requests sent by the client, so the parallelism of the
+// run can be sized accordingly. It's called once when the provider stops.
+func (client *Client) LogThrottlingSummary() {
+	if client.scheduler == nil {
+		return
+	}
+	log.Printf("[INFO] Microsoft Graph throttling summary: %s", client.scheduler.Summary())
 }
 
 func (client *Client) Build(ctx context.Context, o *Option) error {
@@ -46,8 +67,14 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 		}
 		perCallPolicies = append(perCallPolicies, withCorrelationRequestID(id))
 	}
+	if len(o.Defaults.Headers) != 0 {
+		perCallPolicies = append(perCallPolicies, defaultHeadersPolicy{headers: o.Defaults.Headers})
+	}
+	client.scheduler = newThrottlingScheduler(o.MaxConcurrentRequests)
+
 	perRetryPolicies := make([]policy.Policy, 0)
-	perRetryPolicies = append(perRetryPolicies, NewLiveTrafficLogPolicy())
+	perRetryPolicies = append(perRetryPolicies, client.scheduler)
+	perRetryPolicies = append(perRetryPolicies, NewLiveTrafficLogPolicy(NewRedactor(o.RedactedPaths, o.RedactedHeaders)))
 
 	allowedHeaders := []string{
 		"Access-Control-Allow-Methods",
@@ -57,6 +84,7 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 		"Metadata",
 		"Ocp-Automation-Accountid",
 		"P3p",
+		"Retry-After",
 		"Strict-Transport-Security",
 		"Vary",
 		"X-Content-Type-Options",
@@ -70,6 +98,9 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 		"X-Ms-Ratelimit-Remaining-Tenant-Writes",
 		"X-Ms-Request-Id",
 		"X-Ms-Routing-Request-Id",
+		"X-Ms-Throttle-Information",
+		"X-Ms-Throttle-Limit-Percentage",
+		"X-Ms-Throttle-Scope",
 		"X-Xss-Protection",
 	}
 	allowedQueryParams := []string{
@@ -86,19 +117,31 @@ func (client *Client) Build(ctx context.Context, o *Option) error {
 		"$format",
 	}
 
+	var tracingProvider tracing.Provider
+	if o.Tracing.Enabled() {
+		var err error
+		if tracingProvider, err = NewTracingProvider(ctx, o.Tracing); err != nil {
+			return err
+		}
+	}
+
 	msgraphClient, err := NewMSGraphClient(o.Cred, &policy.ClientOptions{
 		Logging: policy.LogOptions{
 			IncludeBody:        false,
 			AllowedHeaders:     allowedHeaders,
 			AllowedQueryParams: allowedQueryParams,
 		},
+		Cloud:            o.CloudCfg,
 		PerCallPolicies:  perCallPolicies,
 		PerRetryPolicies: perRetryPolicies,
+		Transport:        o.Transport,
+		TracingProvider:  tracingProvider,
 	})
 	if err != nil {
 		return err
 	}
 
+	msgraphClient.Defaults = o.Defaults
 	client.MSGraphClient = msgraphClient
 
 	return nil
@@ -0,0 +1,76 @@
+package clients
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/microsoft/terraform-provider-msgraph/internal/retry"
+)
+
+const defaultApiVersion = "v1.0"
+
+// Defaults are the provider-wide defaults of the resources and data sources, the values configured in a resource or data source take precedence.
+type Defaults struct {
+	ApiVersion string
+	// RetryErrorMessageRegex are the patterns of the retryable error messages of the resources and data sources which don't configure `retry`.
+	RetryErrorMessageRegex []regexp.Regexp
+	// Timeouts are the timeouts of the operations, keyed by `create`, `read`, `update` and `delete`.
+	Timeouts map[string]time.Duration
+	// Headers are sent with all requests unless a request sets the same header.
+	Headers               map[string]string
+	IgnoreMissingProperty *bool
+}
+
+// ApiVersionOr returns the API version if it's set, otherwise the default API version, which falls back to `v1.0`.
+func (d Defaults) ApiVersionOr(apiVersion string) string {
+	if apiVersion != "" {
+		return apiVersion
+	}
+	if d.ApiVersion != "" {
+		return d.ApiVersion
+	}
+	return defaultApiVersion
+}
+
+// Timeout returns the default timeout of the operation, or the fallback if there's no default.
+func (d Defaults) Timeout(operation string, fallback time.Duration) time.Duration {
+	if v, ok := d.Timeouts[operation]; ok && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// IgnoreMissingPropertyOr returns the default of `ignore_missing_property`, or the fallback if there's no default.
+func (d Defaults) IgnoreMissingPropertyOr(fallback bool) bool {
+	if d.IgnoreMissingProperty != nil {
+		return *d.IgnoreMissingProperty
+	}
+	return fallback
+}
+
+// RetryOptions returns the retry options of the retry block, or the ones of the default retry patterns if it's not configured.
+func (client *MSGraphClient) RetryOptions(rtry retry.Value) *policy.RetryOptions {
+	if !rtry.IsNull() && !rtry.IsUnknown() {
+		return NewRetryOptions(rtry)
+	}
+	if len(client.Defaults.RetryErrorMessageRegex) == 0 {
+		return nil
+	}
+	return newRetryOptionsForErrorMessages(client.Defaults.RetryErrorMessageRegex)
+}
+
+// defaultHeadersPolicy sets the default headers which aren't set by the request.
+type defaultHeadersPolicy struct {
+	headers map[string]string
+}
+
+func (p defaultHeadersPolicy) Do(req *policy.Request) (*http.Response, error) {
+	for key, value := range p.headers {
+		if req.Raw().Header.Get(key) == "" {
+			req.Raw().Header.Set(key, value)
+		}
+	}
+	return req.Next()
+}
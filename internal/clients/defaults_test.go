@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/microsoft/terraform-provider-msgraph/internal/retry"
+)
+
+func TestDefaults_ApiVersionOr(t *testing.T) {
+	testcases := []struct {
+		defaults   Defaults
+		apiVersion string
+		want       string
+	}{
+		{defaults: Defaults{}, apiVersion: "", want: "v1.0"},
+		{defaults: Defaults{ApiVersion: "beta"}, apiVersion: "", want: "beta"},
+		{defaults: Defaults{ApiVersion: "beta"}, apiVersion: "v1.0", want: "v1.0"},
+	}
+
+	for _, tc := range testcases {
+		if got := tc.defaults.ApiVersionOr(tc.apiVersion); got != tc.want {
+			t.Errorf("ApiVersionOr(%q) with default %q: got %q, want %q", tc.apiVersion, tc.defaults.ApiVersion, got, tc.want)
+		}
+	}
+}
+
+func TestDefaults_Timeout(t *testing.T) {
+	defaults := Defaults{Timeouts: map[string]time.Duration{"create": time.Hour}}
+
+	if got := defaults.Timeout("create", time.Minute); got != time.Hour {
+		t.Errorf("create: got %v, want %v", got, time.Hour)
+	}
+	if got := defaults.Timeout("read", time.Minute); got != time.Minute {
+		t.Errorf("read: got %v, want %v", got, time.Minute)
+	}
+	if got := (Defaults{}).Timeout("delete", time.Minute); got != time.Minute {
+		t.Errorf("no defaults: got %v, want %v", got, time.Minute)
+	}
+}
+
+func TestDefaults_IgnoreMissingPropertyOr(t *testing.T) {
+	disabled := false
+	if got := (Defaults{IgnoreMissingProperty: &disabled}).IgnoreMissingPropertyOr(true); got {
+		t.Errorf("got %v, want false", got)
+	}
+	if got := (Defaults{}).IgnoreMissingPropertyOr(true); !got {
+		t.Errorf("got %v, want true", got)
+	}
+}
+
+func TestMSGraphClient_RetryOptions(t *testing.T) {
+	client := &MSGraphClient{}
+	if got := client.RetryOptions(retry.NewValueNull()); got != nil {
+		t.Errorf("no defaults: got %v, want nil", got)
+	}
+
+	client.Defaults.RetryErrorMessageRegex = []regexp.Regexp{*regexp.MustCompile("replication")}
+	got := client.RetryOptions(retry.NewValueNull())
+	if got == nil || got.ShouldRetry == nil {
+		t.Fatalf("default retry patterns: got %v, want retry options", got)
+	}
+}
+
+func TestDefaultHeadersPolicy(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{defaultHeadersPolicy{headers: map[string]string{
+			"ConsistencyLevel": "eventual",
+			"Prefer":           "return=minimal",
+		}}},
+	})
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Raw().Header.Set("Prefer", "return=representation")
+	if _, err := pl.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := received.Get("ConsistencyLevel"); got != "eventual" {
+		t.Errorf("ConsistencyLevel: got %q, want %q", got, "eventual")
+	}
+	if got := received.Get("Prefer"); got != "return=representation" {
+		t.Errorf("Prefer: got %q, want the header of the request", got)
+	}
+}
@@ -0,0 +1,92 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const deltaTokenQueryParameter = "$deltatoken"
+
+// deltaTokenQueryParameters are the query parameters which carry the state token in the delta links, most workloads use
+// `$deltatoken`, but some of them use `deltatoken` or `$skiptoken`.
+var deltaTokenQueryParameters = []string{deltaTokenQueryParameter, "deltatoken", "$skiptoken"}
+
+// DeltaResult is the result of a delta query round.
+type DeltaResult struct {
+	// Body contains the changes of all pages in `value` and the `@odata.deltaLink` of the last page.
+	Body interface{}
+	// DeltaLink is the URL which is used to fetch the changes since this round.
+	DeltaLink string
+	// DeltaToken is the state token in the DeltaLink.
+	DeltaToken string
+}
+
+// Delta performs a delta query round against the delta function URL, e.g. `/users/delta`, following all
+// `@odata.nextLink` pages until the `@odata.deltaLink` is returned. When the deltaToken of a previous round is
+// specified, only the changes since that round are returned, otherwise the initial round returns the full state.
+func (client *MSGraphClient) Delta(ctx context.Context, url string, apiVersion string, deltaToken string, options RequestOptions) (*DeltaResult, error) {
+	if deltaToken != "" {
+		queryParameters := make(map[string]string, len(options.QueryParameters)+1)
+		for key, value := range options.QueryParameters {
+			queryParameters[key] = value
+		}
+		name, value := deltaTokenQueryParameter, deltaToken
+		if n, v, ok := strings.Cut(deltaToken, "="); ok && n != deltaTokenQueryParameter && isDeltaTokenQueryParameter(n) {
+			name, value = n, v
+		}
+		queryParameters[name] = value
+		options.QueryParameters = queryParameters
+	}
+
+	responseBody, err := client.List(ctx, url, apiVersion, options)
+	if err != nil {
+		return nil, err
+	}
+
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response of the delta query %s", url)
+	}
+	deltaLink, ok := responseMap[deltaLinkKey].(string)
+	if !ok || deltaLink == "" {
+		return nil, fmt.Errorf("the response of %s doesn't contain an %s, make sure the URL refers to a delta function", url, deltaLinkKey)
+	}
+
+	return &DeltaResult{
+		Body:       responseBody,
+		DeltaLink:  deltaLink,
+		DeltaToken: ParseDeltaToken(deltaLink),
+	}, nil
+}
+
+// ParseDeltaToken returns the state token of the delta link. It's the value of the `$deltatoken` query parameter, or, when
+// the delta link carries the state in `deltatoken` or `$skiptoken`, the query parameter and its value, e.g. `$skiptoken=abc`,
+// so that the next round sends the token in the same query parameter.
+func ParseDeltaToken(deltaLink string) string {
+	u, err := url.Parse(deltaLink)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	for _, name := range deltaTokenQueryParameters {
+		if !query.Has(name) {
+			continue
+		}
+		if name == deltaTokenQueryParameter {
+			return query.Get(name)
+		}
+		return name + "=" + query.Get(name)
+	}
+	return ""
+}
+
+func isDeltaTokenQueryParameter(name string) bool {
+	for _, v := range deltaTokenQueryParameters {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDelta(t *testing.T) {
+	var serverUrl string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("$deltatoken") == "abc":
+			_, _ = fmt.Fprintf(w, `{"value":[{"id":"2","@removed":{"reason":"changed"}}],"@odata.deltaLink":"%s/v1.0/users/delta?$deltatoken=def"}`, serverUrl)
+		case query.Get("$skiptoken") == "page2":
+			_, _ = fmt.Fprintf(w, `{"value":[{"id":"2"}],"@odata.deltaLink":"%s/v1.0/users/delta?$deltatoken=abc"}`, serverUrl)
+		default:
+			if query.Get("$select") != "displayName" {
+				t.Errorf("missing query parameters in %s", r.URL.String())
+			}
+			_, _ = fmt.Fprintf(w, `{"@odata.context":"ctx","value":[{"id":"1"}],"@odata.nextLink":"%s/v1.0/users/delta?$skiptoken=page2"}`, serverUrl)
+		}
+	}))
+	serverUrl = client.GraphBaseUrl()
+
+	options := RequestOptions{QueryParameters: map[string]string{"$select": "displayName"}}
+	result, err := client.Delta(context.Background(), "/users/delta", "v1.0", "", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DeltaToken != "abc" {
+		t.Fatalf("unexpected delta token %q", result.DeltaToken)
+	}
+	body := result.Body.(map[string]interface{})
+	if fmt.Sprint(body["value"]) != "[map[id:1] map[id:2]]" || body["@odata.context"] != nil || body["@odata.deltaLink"] != result.DeltaLink {
+		t.Fatalf("unexpected body: %v", body)
+	}
+
+	result, err = client.Delta(context.Background(), "/users/delta", "v1.0", "abc", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DeltaToken != "def" || fmt.Sprint(result.Body.(map[string]interface{})["value"]) != "[map[@removed:map[reason:changed] id:2]]" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, ok := options.QueryParameters["$deltatoken"]; ok {
+		t.Fatalf("the query parameters of the caller must not be modified")
+	}
+}
+
+func TestDelta_NotADeltaFunction(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"value":[]}`)
+	}))
+
+	if _, err := client.Delta(context.Background(), "/users", "v1.0", "", RequestOptions{}); err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+}
+
+func TestParseDeltaToken(t *testing.T) {
+	testcases := []struct {
+		DeltaLink string
+		Expected  string
+	}{
+		{DeltaLink: "https://graph.microsoft.com/v1.0/users/delta?$deltatoken=abc", Expected: "abc"},
+		{DeltaLink: "https://graph.microsoft.com/v1.0/users/delta?deltatoken=abc", Expected: "deltatoken=abc"},
+		{DeltaLink: "https://graph.microsoft.com/v1.0/me/events/delta?$skiptoken=abc", Expected: "$skiptoken=abc"},
+		{DeltaLink: "https://graph.microsoft.com/v1.0/users/delta?$select=displayName", Expected: ""},
+		{DeltaLink: "%zz", Expected: ""},
+	}
+
+	for _, tc := range testcases {
+		if actual := ParseDeltaToken(tc.DeltaLink); actual != tc.Expected {
+			t.Errorf("ParseDeltaToken(%q): expected %q, got %q", tc.DeltaLink, tc.Expected, actual)
+		}
+	}
+}
+
+func TestDelta_SkipTokenDeltaLink(t *testing.T) {
+	var serverUrl string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("$skiptoken") == "abc":
+			if query.Has("$deltatoken") {
+				t.Errorf("unexpected $deltatoken in %s", r.URL.String())
+			}
+			_, _ = fmt.Fprintf(w, `{"value":[],"@odata.deltaLink":"%s/v1.0/me/events/delta?$skiptoken=def"}`, serverUrl)
+		default:
+			_, _ = fmt.Fprintf(w, `{"value":[{"id":"1"}],"@odata.deltaLink":"%s/v1.0/me/events/delta?$skiptoken=abc"}`, serverUrl)
+		}
+	}))
+	serverUrl = client.GraphBaseUrl()
+
+	result, err := client.Delta(context.Background(), "/me/events/delta", "v1.0", "", RequestOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DeltaToken != "$skiptoken=abc" {
+		t.Fatalf("unexpected delta token %q", result.DeltaToken)
+	}
+
+	result, err = client.Delta(context.Background(), "/me/events/delta", "v1.0", result.DeltaToken, RequestOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.DeltaToken != "$skiptoken=def" {
+		t.Fatalf("unexpected delta token %q", result.DeltaToken)
+	}
+}
@@ -0,0 +1,131 @@
+package clients
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// ServiceNameMSGraph is the name of the Microsoft Graph service in a cloud.Configuration.
+const ServiceNameMSGraph cloud.ServiceName = "msgraph"
+
+const (
+	EnvironmentPublic       = "public"
+	EnvironmentUSGovernment = "usgovernment"
+	EnvironmentDoD          = "dod"
+	EnvironmentChina        = "china"
+)
+
+// Environments contains the names of the supported national clouds.
+var Environments = []string{
+	EnvironmentPublic,
+	EnvironmentUSGovernment,
+	EnvironmentDoD,
+	EnvironmentChina,
+}
+
+type environment struct {
+	authorityHost string
+	graphEndpoint string
+}
+
+var environments = map[string]environment{
+	EnvironmentPublic: {
+		authorityHost: cloud.AzurePublic.ActiveDirectoryAuthorityHost,
+		graphEndpoint: "https://graph.microsoft.com",
+	},
+	// US Government L4
+	EnvironmentUSGovernment: {
+		authorityHost: cloud.AzureGovernment.ActiveDirectoryAuthorityHost,
+		graphEndpoint: "https://graph.microsoft.us",
+	},
+	// US Government L5 (DoD)
+	EnvironmentDoD: {
+		authorityHost: cloud.AzureGovernment.ActiveDirectoryAuthorityHost,
+		graphEndpoint: "https://dod-graph.microsoft.us",
+	},
+	// China operated by 21Vianet
+	EnvironmentChina: {
+		authorityHost: cloud.AzureChina.ActiveDirectoryAuthorityHost,
+		graphEndpoint: "https://microsoftgraph.chinacloudapi.cn",
+	},
+}
+
+// NewCloudConfiguration builds the cloud.Configuration of the given environment. The graphEndpoint and authorityHost
+// override the environment's defaults when they're not empty. The token audience always stays the one of the
+// environment's Microsoft Graph endpoint, so that a custom endpoint can be used to proxy the Microsoft Graph API.
+func NewCloudConfiguration(environmentName string, graphEndpoint string, authorityHost string) (cloud.Configuration, error) {
+	if environmentName == "" {
+		environmentName = EnvironmentPublic
+	}
+	env, ok := environments[strings.ToLower(environmentName)]
+	if !ok {
+		return cloud.Configuration{}, fmt.Errorf("unknown environment %q, supported values are: %s", environmentName, strings.Join(Environments, ", "))
+	}
+
+	audience := env.graphEndpoint
+	endpoint := env.graphEndpoint
+	if graphEndpoint != "" {
+		if err := validateEndpoint(graphEndpoint); err != nil {
+			return cloud.Configuration{}, fmt.Errorf("invalid graph endpoint %q: %v", graphEndpoint, err)
+		}
+		endpoint = strings.TrimSuffix(graphEndpoint, "/")
+	}
+
+	authority := env.authorityHost
+	if authorityHost != "" {
+		if err := validateEndpoint(authorityHost); err != nil {
+			return cloud.Configuration{}, fmt.Errorf("invalid authority host %q: %v", authorityHost, err)
+		}
+		authority = authorityHost
+		if !strings.HasSuffix(authority, "/") {
+			authority += "/"
+		}
+	}
+
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: authority,
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			ServiceNameMSGraph: {
+				Audience: audience,
+				Endpoint: endpoint,
+			},
+		},
+	}, nil
+}
+
+func validateEndpoint(input string) error {
+	u, err := url.Parse(input)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("the scheme must be either https or http")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("the host must not be empty")
+	}
+	return nil
+}
+
+// graphServiceConfiguration returns the Microsoft Graph service configuration of the cloud, it falls back to the
+// public cloud when the configuration doesn't contain the Microsoft Graph service.
+func graphServiceConfiguration(cfg cloud.Configuration) cloud.ServiceConfiguration {
+	if v, ok := cfg.Services[ServiceNameMSGraph]; ok && v.Endpoint != "" {
+		if v.Audience == "" {
+			v.Audience = v.Endpoint
+		}
+		return v
+	}
+	return cloud.ServiceConfiguration{
+		Audience: environments[EnvironmentPublic].graphEndpoint,
+		Endpoint: environments[EnvironmentPublic].graphEndpoint,
+	}
+}
+
+// graphScope returns the scope of the access tokens for Microsoft Graph, e.g. `https://graph.microsoft.com/.default`.
+func graphScope(cfg cloud.Configuration) string {
+	return strings.TrimSuffix(graphServiceConfiguration(cfg).Audience, "/") + "/.default"
+}
@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+func TestNewCloudConfiguration(t *testing.T) {
+	testcases := []struct {
+		name          string
+		environment   string
+		graphEndpoint string
+		authorityHost string
+		wantAuthority string
+		wantEndpoint  string
+		wantAudience  string
+		wantErr       bool
+	}{
+		{
+			name:          "default to public",
+			wantAuthority: "https://login.microsoftonline.com/",
+			wantEndpoint:  "https://graph.microsoft.com",
+			wantAudience:  "https://graph.microsoft.com",
+		},
+		{
+			name:          "us government",
+			environment:   "usgovernment",
+			wantAuthority: "https://login.microsoftonline.us/",
+			wantEndpoint:  "https://graph.microsoft.us",
+			wantAudience:  "https://graph.microsoft.us",
+		},
+		{
+			name:          "dod is case insensitive",
+			environment:   "DoD",
+			wantAuthority: "https://login.microsoftonline.us/",
+			wantEndpoint:  "https://dod-graph.microsoft.us",
+			wantAudience:  "https://dod-graph.microsoft.us",
+		},
+		{
+			name:          "china",
+			environment:   "china",
+			wantAuthority: "https://login.chinacloudapi.cn/",
+			wantEndpoint:  "https://microsoftgraph.chinacloudapi.cn",
+			wantAudience:  "https://microsoftgraph.chinacloudapi.cn",
+		},
+		{
+			name:          "custom endpoints",
+			environment:   "public",
+			graphEndpoint: "http://localhost:8080/",
+			authorityHost: "https://login.example.com",
+			wantAuthority: "https://login.example.com/",
+			wantEndpoint:  "http://localhost:8080",
+			wantAudience:  "https://graph.microsoft.com",
+		},
+		{
+			name:        "unknown environment",
+			environment: "german",
+			wantErr:     true,
+		},
+		{
+			name:          "invalid graph endpoint",
+			graphEndpoint: "graph.microsoft.com",
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewCloudConfiguration(tc.environment, tc.graphEndpoint, tc.authorityHost)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ActiveDirectoryAuthorityHost != tc.wantAuthority {
+				t.Errorf("authority host: got %q, want %q", got.ActiveDirectoryAuthorityHost, tc.wantAuthority)
+			}
+			graph := got.Services[ServiceNameMSGraph]
+			if graph.Endpoint != tc.wantEndpoint {
+				t.Errorf("endpoint: got %q, want %q", graph.Endpoint, tc.wantEndpoint)
+			}
+			if graph.Audience != tc.wantAudience {
+				t.Errorf("audience: got %q, want %q", graph.Audience, tc.wantAudience)
+			}
+		})
+	}
+}
+
+func TestGraphServiceConfiguration_DefaultsToPublic(t *testing.T) {
+	got := graphServiceConfiguration(cloud.Configuration{})
+	if got.Endpoint != "https://graph.microsoft.com" || got.Audience != "https://graph.microsoft.com" {
+		t.Fatalf("unexpected configuration: %+v", got)
+	}
+}
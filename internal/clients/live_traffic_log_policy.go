@@ -5,7 +5,6 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
@@ -14,56 +13,55 @@ import (
 const redactedValue = "REDACTED"
 
 type liveTrafficLogPolicy struct {
-	notAllowedHeaders map[string]bool
+	redactor *Redactor
 }
 
-type traffic struct {
-	LiveRequest  liveRequest  `json:"request"`
-	LiveResponse liveResponse `json:"response"`
+// Traffic is a request and its response, it's serialised to JSON in the debug logs and in the recordings of the acceptance tests.
+type Traffic struct {
+	LiveRequest  LiveRequest  `json:"request"`
+	LiveResponse LiveResponse `json:"response"`
 }
 
-type liveRequest struct {
+type LiveRequest struct {
 	Headers map[string]string `json:"headers"`
 	Method  string            `json:"method"`
 	Url     string            `json:"url"`
 	Body    string            `json:"body"`
 }
 
-type liveResponse struct {
+type LiveResponse struct {
 	StatusCode int               `json:"statusCode"`
 	Headers    map[string]string `json:"headers"`
 	Body       string            `json:"body"`
 }
 
-func NewLiveTrafficLogPolicy() policy.Policy {
+func NewLiveTrafficLogPolicy(redactor *Redactor) policy.Policy {
 	return &liveTrafficLogPolicy{
-		notAllowedHeaders: map[string]bool{
-			"authorization": true,
-		},
+		redactor: redactor,
 	}
 }
 
 func (p *liveTrafficLogPolicy) Do(req *policy.Request) (*http.Response, error) {
 	rawRequest := req.Raw()
-	liveReq := liveRequest{
-		Headers: p.header(rawRequest.Header),
+	liveReq := LiveRequest{
+		Headers: p.redactor.RedactHeaders(rawRequest.Header),
 		Method:  rawRequest.Method,
 		Url:     rawRequest.URL.String(),
-		Body:    p.requestBodyString(req),
+		Body:    p.redactor.RedactBody(p.requestBodyString(req)),
 	}
 	if err := req.RewindBody(); err != nil {
 		return nil, err
 	}
 	response, err := req.Next() // Make the request
-	liveResp := liveResponse{}
+	liveResp := LiveResponse{}
 	if err == nil {
-		liveResp.Headers = p.header(response.Header)
+		liveResp.Headers = p.redactor.RedactHeaders(response.Header)
 		liveResp.StatusCode = response.StatusCode
-		liveResp.Body = p.responseBodyString(response)
+		liveResp.Body = p.redactor.RedactBody(p.responseBodyString(response))
 	} else {
 		liveResp.Body = err.Error()
 	}
-	liveTraffic := traffic{
+	liveTraffic := Traffic{
 		LiveRequest:  liveReq,
 		LiveResponse: liveResp,
 	}
@@ -102,15 +100,3 @@ func (p *liveTrafficLogPolicy) responseBodyString(resp *http.Response) string {
 	}
 	return string(body)
 }
-
-func (p *liveTrafficLogPolicy) header(input http.Header) map[string]string {
-	output := make(map[string]string)
-	for k, v := range input {
-		if p.notAllowedHeaders[strings.ToLower(k)] {
-			output[k] = redactedValue
-		} else {
-			output[k] = strings.Join(v, ",")
-		}
-	}
-	return output
-}
@@ -0,0 +1,237 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	headerOperationLocation = "Operation-Location"
+	headerLocation          = "Location"
+	headerRetryAfter        = "Retry-After"
+	headerRetryAfterMS      = "Retry-After-Ms"
+)
+
+// defaultPollingInterval is used between two polling requests when the service doesn't return a Retry-After header.
+var defaultPollingInterval = 5 * time.Second
+
+// LongRunningOperationError is returned when a long-running operation finishes with a failed status.
+type LongRunningOperationError struct {
+	OperationUrl string
+	Status       string
+	Code         string
+	Message      string
+}
+
+func (e *LongRunningOperationError) Error() string {
+	msg := fmt.Sprintf("long-running operation %s finished with status %q", e.OperationUrl, e.Status)
+	if e.Code != "" {
+		msg += fmt.Sprintf(", code: %s", e.Code)
+	}
+	if e.Message != "" {
+		msg += fmt.Sprintf(", message: %s", e.Message)
+	}
+	return msg
+}
+
+// isLongRunningOperation checks whether the response is a 202 Accepted response which refers to an operation that
+// should be polled.
+func isLongRunningOperation(resp *http.Response, options RequestOptions) bool {
+	if !options.PollLongRunningOperation || resp == nil || resp.StatusCode != http.StatusAccepted {
+		return false
+	}
+	return operationLocation(resp) != ""
+}
+
+// operationLocation returns the URL of the operation which is returned in the Operation-Location or Location header.
+func operationLocation(resp *http.Response) string {
+	if v := resp.Header.Get(headerOperationLocation); v != "" {
+		return v
+	}
+	return resp.Header.Get(headerLocation)
+}
+
+// pollLongRunningOperation polls the operation referenced by the 202 Accepted response until it reaches a terminal
+// status. If the operation refers to a resource when it succeeds, the resource is returned, otherwise the operation
+// itself is returned, unless createsResource is set, e.g. for a create request whose result must be the created
+// resource, then an error is returned. The polling stops when the context is cancelled, e.g. the resource timeout is reached.
+func (client *MSGraphClient) pollLongRunningOperation(ctx context.Context, resp *http.Response, apiVersion string, createsResource bool) (interface{}, error) {
+	pollingUrl := client.absoluteUrl(operationLocation(resp), apiVersion)
+	// the Location header of the accepted response refers to the resource when the operation is in the Operation-Location header
+	resourceUrl := ""
+	if resp.Header.Get(headerOperationLocation) != "" {
+		resourceUrl = resp.Header.Get(headerLocation)
+	}
+	delay := retryAfter(resp)
+	for {
+		log.Printf("[DEBUG] Waiting %s before polling the long-running operation %s", delay, pollingUrl)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for the long-running operation %s: %w", pollingUrl, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		resp, err := client.get(ctx, pollingUrl)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusAccepted {
+			delay = retryAfter(resp)
+			continue
+		}
+		if !runtime.HasStatusCode(resp, http.StatusOK) {
+			return nil, runtime.NewResponseError(resp)
+		}
+
+		var responseBody interface{}
+		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+			return nil, err
+		}
+		operation, ok := responseBody.(map[string]interface{})
+		if !ok {
+			return responseBody, nil
+		}
+		status, ok := operation["status"].(string)
+		if !ok {
+			// the location refers to the resource itself rather than an operation
+			return responseBody, nil
+		}
+
+		switch strings.ToLower(status) {
+		case "succeeded", "completed":
+			location := ""
+			for _, key := range []string{"resourceLocation", "targetResourceLocation"} {
+				if v, ok := operation[key].(string); ok && v != "" {
+					location = v
+					break
+				}
+			}
+			if v := resp.Header.Get(headerLocation); location == "" && v != "" && client.absoluteUrl(v, apiVersion) != pollingUrl {
+				location = v
+			}
+			if location == "" {
+				location = resourceUrl
+			}
+			if location != "" {
+				return client.readResource(ctx, client.absoluteUrl(location, apiVersion))
+			}
+			if createsResource {
+				return nil, fmt.Errorf("the long-running operation %s succeeded, but it doesn't refer to the created resource "+
+					"in its resourceLocation, its targetResourceLocation or a Location header", pollingUrl)
+			}
+			return responseBody, nil
+		case "failed", "canceled", "cancelled":
+			return nil, newLongRunningOperationError(pollingUrl, status, operation)
+		default:
+			delay = retryAfter(resp)
+		}
+	}
+}
+
+// readResource reads the resource which is referred to by a succeeded long-running operation.
+func (client *MSGraphClient) readResource(ctx context.Context, url string) (interface{}, error) {
+	resp, err := client.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, runtime.NewResponseError(resp)
+	}
+	var resource interface{}
+	if err := runtime.UnmarshalAsJSON(resp, &resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+func (client *MSGraphClient) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	if !client.isGraphUrl(url) {
+		// the bearer token is only sent to Microsoft Graph, an operation hosted elsewhere is polled anonymously
+		return client.unauthenticatedPl.Do(req)
+	}
+	return client.pl.Do(req)
+}
+
+// isGraphUrl returns whether the URL has the same scheme and host as the Microsoft Graph endpoint of the client.
+func (client *MSGraphClient) isGraphUrl(rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	host, err := url.Parse(client.host)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, host.Scheme) && strings.EqualFold(u.Host, host.Host)
+}
+
+// absoluteUrl converts the location returned by the service to an absolute URL. The location could be an absolute
+// URL, a path which contains the API version, or a path which is relative to the API version.
+func (client *MSGraphClient) absoluteUrl(location string, apiVersion string) string {
+	if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://") {
+		return location
+	}
+	location = "/" + strings.TrimPrefix(location, "/")
+	for _, version := range []string{"v1.0", "beta"} {
+		if strings.HasPrefix(location, "/"+version+"/") {
+			return client.host + location
+		}
+	}
+	return client.host + "/" + apiVersion + location
+}
+
+func newLongRunningOperationError(operationUrl string, status string, operation map[string]interface{}) *LongRunningOperationError {
+	out := &LongRunningOperationError{
+		OperationUrl: operationUrl,
+		Status:       status,
+	}
+	if errorMap, ok := operation["error"].(map[string]interface{}); ok {
+		out.Code, _ = errorMap["code"].(string)
+		out.Message, _ = errorMap["message"].(string)
+	}
+	if out.Message == "" {
+		out.Message, _ = operation["statusDetail"].(string)
+	}
+	return out
+}
+
+// retryAfter returns the delay which is requested by the service, it falls back to the default polling interval.
+func retryAfter(resp *http.Response) time.Duration {
+	if d := parseRetryAfter(resp); d > 0 {
+		return d
+	}
+	return defaultPollingInterval
+}
+
+// parseRetryAfter returns the delay in the Retry-After-Ms or Retry-After header, it returns 0 if there's none.
+func parseRetryAfter(resp *http.Response) time.Duration {
+	if v := resp.Header.Get(headerRetryAfterMS); v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms > 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	if v := resp.Header.Get(headerRetryAfter); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *MSGraphClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewMSGraphClient(fakeCredential{}, &policy.ClientOptions{
+		Cloud: cloud.Configuration{
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				ServiceNameMSGraph: {Endpoint: server.URL, Audience: "https://graph.microsoft.com"},
+			},
+		},
+		Retry: policy.RetryOptions{MaxRetries: -1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestPollLongRunningOperation(t *testing.T) {
+	defaultPollingInterval = time.Millisecond
+	t.Cleanup(func() { defaultPollingInterval = 5 * time.Second })
+
+	testcases := []struct {
+		name              string
+		location          string
+		operationLocation string
+		operations        []string
+		// resourceLocation is returned in the Location header of the last polling response
+		resourceLocation string
+		poll             bool
+		want             string
+		wantErr          string
+		wantFailed       bool
+	}{
+		{
+			name:       "operation refers to the created resource",
+			location:   "/teams('1')/operations('2')",
+			operations: []string{`{"status":"inProgress"}`, `{"status":"succeeded","targetResourceLocation":"/teams('1')"}`},
+			poll:       true,
+			want:       "map[id:1]",
+		},
+		{
+			name:             "operation refers to the created resource in the Location header",
+			location:         "/v1.0/operations/2",
+			operations:       []string{`{"status":"running"}`, `{"status":"succeeded"}`},
+			resourceLocation: "/teams('1')",
+			poll:             true,
+			want:             "map[id:1]",
+		},
+		{
+			name:              "accepted response refers to the created resource in the Location header",
+			location:          "/teams('1')",
+			operationLocation: "/v1.0/operations/2",
+			operations:        []string{`{"status":"running"}`, `{"status":"succeeded"}`},
+			poll:              true,
+			want:              "map[id:1]",
+		},
+		{
+			name:       "operation without resource location",
+			location:   "/v1.0/operations/2",
+			operations: []string{`{"status":"running"}`, `{"status":"succeeded"}`},
+			poll:       true,
+			wantErr:    "doesn't refer to the created resource in its resourceLocation, its targetResourceLocation or a Location header",
+		},
+		{
+			name:       "failed operation",
+			location:   "/operations/2",
+			operations: []string{`{"status":"failed","error":{"code":"BadRequest","message":"boom"}}`},
+			poll:       true,
+			wantErr:    `finished with status "failed", code: BadRequest, message: boom`,
+			wantFailed: true,
+		},
+		{
+			name:     "polling is disabled",
+			location: "/operations/2",
+			poll:     false,
+			want:     "<nil>",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var polled int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v1.0/teams", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", tc.location)
+				if tc.operationLocation != "" {
+					w.Header().Set("Operation-Location", tc.operationLocation)
+				}
+				w.WriteHeader(http.StatusAccepted)
+			})
+			mux.HandleFunc("/v1.0/teams('1')", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, `{"id":"1"}`)
+			})
+			operationHandler := func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&polled, 1) - 1
+				w.Header().Set("Retry-After-Ms", "1")
+				if tc.resourceLocation != "" && int(i) == len(tc.operations)-1 {
+					w.Header().Set("Location", tc.resourceLocation)
+				}
+				_, _ = fmt.Fprint(w, tc.operations[i])
+			}
+			mux.HandleFunc("/v1.0/teams('1')/operations('2')", operationHandler)
+			mux.HandleFunc("/v1.0/operations/2", operationHandler)
+
+			client := newTestClient(t, mux)
+			got, err := client.Create(context.Background(), "/teams", "v1.0", map[string]interface{}{}, RequestOptions{PollLongRunningOperation: tc.poll})
+			if tc.wantErr != "" {
+				var lroErr *LongRunningOperationError
+				if tc.wantFailed && !errors.As(err, &lroErr) {
+					t.Fatalf("expected a long-running operation error, got %v", err)
+				}
+				if err == nil || !strings.HasSuffix(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error message: %s", err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(got) != tc.want {
+				t.Fatalf("got %v, want %s", got, tc.want)
+			}
+			if int(polled) != len(tc.operations) {
+				t.Fatalf("polled %d times, want %d", polled, len(tc.operations))
+			}
+		})
+	}
+}
+
+func TestPollLongRunningOperation_ForeignHost(t *testing.T) {
+	defaultPollingInterval = time.Millisecond
+	t.Cleanup(func() { defaultPollingInterval = 5 * time.Second })
+
+	var authorization atomic.Value
+	authorization.Store("")
+	operations := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		_, _ = fmt.Fprint(w, `{"status":"succeeded"}`)
+	}))
+	t.Cleanup(operations.Close)
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Operation-Location", operations.URL+"/operations/1")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	got, err := client.Action(context.Background(), http.MethodPost, "/teams/1/archive", "v1.0", map[string]interface{}{}, RequestOptions{PollLongRunningOperation: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(got) != "map[status:succeeded]" {
+		t.Fatalf("unexpected result: %v", got)
+	}
+	if v := authorization.Load().(string); v != "" {
+		t.Fatalf("the bearer token was sent to the foreign host: %q", v)
+	}
+}
+
+func TestPollLongRunningOperation_RespectsContext(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Operation-Location", "/operations/1")
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.Delete(ctx, "/groups/1", "v1.0", RequestOptions{PollLongRunningOperation: true})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	testcases := []struct {
+		header http.Header
+		want   time.Duration
+	}{
+		{header: http.Header{}, want: defaultPollingInterval},
+		{header: http.Header{"Retry-After": []string{"3"}}, want: 3 * time.Second},
+		{header: http.Header{"Retry-After-Ms": []string{"250"}, "Retry-After": []string{"3"}}, want: 250 * time.Millisecond},
+		{header: http.Header{"Retry-After": []string{"invalid"}}, want: defaultPollingInterval},
+	}
+
+	for _, tc := range testcases {
+		if got := retryAfter(&http.Response{Header: tc.header}); got != tc.want {
+			t.Errorf("retryAfter(%v): got %s, want %s", tc.header, got, tc.want)
+		}
+	}
+}
@@ -3,48 +3,83 @@ package clients
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"github.com/microsoft/terraform-provider-msgraph/version"
 )
 
 const (
 	moduleName    = "resource"
 	moduleVersion = "v0.1.0"
 	nextLinkKey   = "@odata.nextLink"
+	deltaLinkKey  = "@odata.deltaLink"
+	countKey      = "@odata.count"
 )
 
 type MSGraphClient struct {
 	host string
 	pl   runtime.Pipeline
+	// unauthenticatedPl sends the requests to the URLs outside of Microsoft Graph without a bearer token, e.g. the
+	// pre-authenticated URLs of the upload sessions, which reject bearer tokens.
+	unauthenticatedPl runtime.Pipeline
+	tracer            tracing.Tracer
+
+	Defaults Defaults
 }
 
 func NewMSGraphClient(credential azcore.TokenCredential, opt *policy.ClientOptions) (*MSGraphClient, error) {
+	if opt == nil {
+		opt = &policy.ClientOptions{}
+	}
+	graphCfg := graphServiceConfiguration(opt.Cloud)
 	pl := runtime.NewPipeline(moduleName, moduleVersion, runtime.PipelineOptions{
 		AllowedHeaders:         nil,
 		AllowedQueryParameters: nil,
 		APIVersion:             runtime.APIVersionOptions{},
-		PerCall:                nil,
+		PerCall:                []policy.Policy{backoffRetryPolicy{}},
 		PerRetry: []policy.Policy{
-			runtime.NewBearerTokenPolicy(credential, []string{"https://graph.microsoft.com/.default"}, nil),
+			runtime.NewBearerTokenPolicy(credential, []string{graphScope(opt.Cloud)}, &policy.BearerTokenOptions{
+				// allow a local Microsoft Graph stand-in which is served over HTTP
+				InsecureAllowCredentialWithHTTP: strings.HasPrefix(graphCfg.Endpoint, "http://"),
+			}),
 		},
 		Tracing: runtime.TracingOptions{},
 	}, opt)
 	return &MSGraphClient{
-		host: "https://graph.microsoft.com",
+		host: graphCfg.Endpoint,
 		pl:   pl,
+		// the policies of the client, e.g. the default headers, the correlation id and the throttling scheduler, are meant
+		// for Microsoft Graph, so only the transport and the retry options are shared with the requests to other hosts
+		unauthenticatedPl: runtime.NewPipeline(moduleName, moduleVersion, runtime.PipelineOptions{
+			PerCall: []policy.Policy{backoffRetryPolicy{}},
+		}, &policy.ClientOptions{
+			Retry:     opt.Retry,
+			Transport: opt.Transport,
+		}),
+		tracer: opt.TracingProvider.NewTracer(tracerName, version.ProviderVersion),
 	}, nil
 }
 
 func (client *MSGraphClient) Read(ctx context.Context, url string, apiVersion string, options RequestOptions) (interface{}, error) {
+	responseBody, _, err := client.ReadWithPaging(ctx, url, apiVersion, options, PagingOptions{})
+	return responseBody, err
+}
+
+// ReadWithPaging reads the resource, if the response is a page of a collection, the following pages are fetched within
+// the bounds of the paging options. It returns whether the collection was truncated by the paging options.
+func (client *MSGraphClient) ReadWithPaging(ctx context.Context, url string, apiVersion string, options RequestOptions, paging PagingOptions) (interface{}, bool, error) {
+	options = withConsistencyLevel(paging.Apply(options))
 	// apply per-request retry options via context
 	if options.RetryOptions != nil {
-		ctx = policy.WithRetryOptions(ctx, *options.RetryOptions)
+		ctx = withRetryOptions(ctx, options.RetryOptions)
 	}
 	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.host, apiVersion, url))
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	reqQP := req.Raw().URL.Query()
 	for key, value := range options.QueryParameters {
@@ -57,28 +92,90 @@ func (client *MSGraphClient) Read(ctx context.Context, url string, apiVersion st
 	}
 	resp, err := client.pl.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if !runtime.HasStatusCode(resp, http.StatusOK) {
-		return nil, runtime.NewResponseError(resp)
+		return nil, false, runtime.NewResponseError(resp)
 	}
 
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
+	responseBody, err := unmarshalResponseBody(resp)
+	if err != nil {
+		return nil, false, err
 	}
 
 	// if response has nextLink, follow the link and return the final response
 	if responseBodyMap, ok := responseBody.(map[string]interface{}); ok {
-		if nextLink := responseBodyMap["@odata.nextLink"]; nextLink != nil {
-			return client.List(ctx, url, apiVersion, options)
+		if nextLink := responseBodyMap[nextLinkKey]; nextLink != nil {
+			return client.list(ctx, url, apiVersion, options, responseBody, paging)
+		}
+		// the collection fits in a single page, which is still bounded by the max items
+		if value, ok := responseBodyMap["value"].([]interface{}); ok && paging.MaxItems > 0 && len(value) > paging.MaxItems {
+			responseBodyMap["value"] = value[:paging.MaxItems]
+			return responseBodyMap, true, nil
 		}
 	}
 
-	return responseBody, nil
+	return responseBody, false, nil
+}
+
+// ReadRaw reads the content of the resource as it is, e.g. the `$value` of a user's photo or of a drive item.
+func (client *MSGraphClient) ReadRaw(ctx context.Context, url string, apiVersion string, options RequestOptions) (*RawContent, error) {
+	if options.RetryOptions != nil {
+		ctx = withRetryOptions(ctx, options.RetryOptions)
+	}
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(client.host, apiVersion, url))
+	if err != nil {
+		return nil, err
+	}
+	reqQP := req.Raw().URL.Query()
+	for key, value := range options.QueryParameters {
+		reqQP.Set(key, value)
+	}
+	req.Raw().URL.RawQuery = reqQP.Encode()
+	req.Raw().Header.Set("Accept", "*/*")
+	for key, value := range options.Headers {
+		req.Raw().Header.Set(key, value)
+	}
+	resp, err := client.pl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, runtime.NewResponseError(resp)
+	}
+
+	data, err := runtime.Payload(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &RawContent{
+		ContentType: resp.Header.Get("Content-Type"),
+		Data:        data,
+	}, nil
 }
 
 func (client *MSGraphClient) List(ctx context.Context, url string, apiVersion string, options RequestOptions) (interface{}, error) {
+	responseBody, _, err := client.list(ctx, url, apiVersion, options, nil, PagingOptions{})
+	return responseBody, err
+}
+
+// FollowNextLinks fetches the pages following the first page of a collection, e.g. the response of an action, within the
+// bounds of the paging options. It returns whether the collection was truncated by the paging options.
+func (client *MSGraphClient) FollowNextLinks(ctx context.Context, firstPage interface{}, options RequestOptions, paging PagingOptions) (interface{}, bool, error) {
+	firstPageMap, ok := firstPage.(map[string]interface{})
+	if !ok {
+		return firstPage, false, nil
+	}
+	if _, ok := firstPageMap["value"].([]interface{}); !ok {
+		return firstPage, false, nil
+	}
+	return client.list(ctx, "", "", options, firstPage, paging)
+}
+
+// list fetches the pages of a collection and merges their items. The firstPage, if specified, is used instead of
+// fetching the first page from the url.
+func (client *MSGraphClient) list(ctx context.Context, url string, apiVersion string, options RequestOptions, firstPage interface{}, paging PagingOptions) (interface{}, bool, error) {
+	options = withConsistencyLevel(options)
 	pager := runtime.NewPager(runtime.PagingHandler[interface{}]{
 		More: func(current interface{}) bool {
 			if current == nil {
@@ -97,8 +194,11 @@ func (client *MSGraphClient) List(ctx context.Context, url string, apiVersion st
 			return true
 		},
 		Fetcher: func(ctx context.Context, current *interface{}) (interface{}, error) {
+			if current == nil && firstPage != nil {
+				return firstPage, nil
+			}
 			if options.RetryOptions != nil {
-				ctx = policy.WithRetryOptions(ctx, *options.RetryOptions)
+				ctx = withRetryOptions(ctx, options.RetryOptions)
 			}
 			var request *policy.Request
 			if current == nil {
@@ -124,6 +224,12 @@ func (client *MSGraphClient) List(ctx context.Context, url string, apiVersion st
 				if err != nil {
 					return nil, err
 				}
+				// the following pages of an advanced query also require the consistency level
+				for key, value := range options.Headers {
+					if strings.EqualFold(key, consistencyLevelHeader) {
+						req.Raw().Header.Set(key, value)
+					}
+				}
 				request = req
 			}
 			request.Raw().Header.Set("Accept", "application/json")
@@ -144,39 +250,54 @@ func (client *MSGraphClient) List(ctx context.Context, url string, apiVersion st
 
 	out := make(map[string]interface{})
 	value := make([]interface{}, 0)
+	truncated := false
+	pages := 0
 	for pager.More() {
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
+		pages++
 
-		if pageMap, ok := page.(map[string]interface{}); ok {
-			if pageMap["value"] != nil {
-				if pageValue, ok := pageMap["value"].([]interface{}); ok {
-					value = append(value, pageValue...)
-					continue
-				}
-			}
-			// copy all fields except for nextLinkKey and value
-			for key, val := range pageMap {
-				if key != nextLinkKey && key != "value" {
-					out[key] = val
-				}
-			}
+		pageMap, ok := page.(map[string]interface{})
+		if !ok {
+			return page, false, nil
+		}
+		pageValue, ok := pageMap["value"].([]interface{})
+		if !ok {
+			// if response doesn't follow the paging guideline, return the response as is
+			return page, false, nil
 		}
+		value = append(value, pageValue...)
 
-		// if response doesn't follow the paging guideline, return the response as is
-		return page, nil
+		// only the @odata.deltaLink of the last page and the @odata.count are kept, the @odata.count is only returned
+		// with the first page
+		if val, ok := pageMap[deltaLinkKey]; ok {
+			out[deltaLinkKey] = val
+		}
+		if val, ok := pageMap[countKey]; ok && out[countKey] == nil {
+			out[countKey] = val
+		}
+
+		if paging.MaxItems > 0 && len(value) >= paging.MaxItems {
+			truncated = len(value) > paging.MaxItems || pager.More()
+			value = value[:paging.MaxItems]
+			break
+		}
+		if paging.MaxPages > 0 && pages >= paging.MaxPages {
+			truncated = pager.More()
+			break
+		}
 	}
 
 	out["value"] = value
 
-	return out, nil
+	return out, truncated, nil
 }
 
 func (client *MSGraphClient) Create(ctx context.Context, url string, apiVersion string, body interface{}, options RequestOptions) (interface{}, error) {
 	if options.RetryOptions != nil {
-		ctx = policy.WithRetryOptions(ctx, *options.RetryOptions)
+		ctx = withRetryOptions(ctx, options.RetryOptions)
 	}
 	req, err := runtime.NewRequest(ctx, http.MethodPost, runtime.JoinPaths(client.host, apiVersion, url))
 	if err != nil {
@@ -191,7 +312,7 @@ func (client *MSGraphClient) Create(ctx context.Context, url string, apiVersion
 	for key, value := range options.Headers {
 		req.Raw().Header.Set(key, value)
 	}
-	if err := runtime.MarshalAsJSON(req, body); err != nil {
+	if err := setRequestBody(req, body); err != nil {
 		return nil, err
 	}
 	resp, err := client.pl.Do(req)
@@ -202,18 +323,16 @@ func (client *MSGraphClient) Create(ctx context.Context, url string, apiVersion
 		return nil, runtime.NewResponseError(resp)
 	}
 
-	// TODO: Handle long-running operations if needed
-
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
+	if isLongRunningOperation(resp, options) {
+		return client.pollLongRunningOperation(ctx, resp, apiVersion, true)
 	}
-	return responseBody, nil
+
+	return unmarshalResponseBody(resp)
 }
 
 func (client *MSGraphClient) Update(ctx context.Context, url string, apiVersion string, body interface{}, options RequestOptions) (interface{}, error) {
 	if options.RetryOptions != nil {
-		ctx = policy.WithRetryOptions(ctx, *options.RetryOptions)
+		ctx = withRetryOptions(ctx, options.RetryOptions)
 	}
 	req, err := runtime.NewRequest(ctx, http.MethodPatch, runtime.JoinPaths(client.host, apiVersion, url))
 	if err != nil {
@@ -228,29 +347,27 @@ func (client *MSGraphClient) Update(ctx context.Context, url string, apiVersion
 	for key, value := range options.Headers {
 		req.Raw().Header.Set(key, value)
 	}
-	if err := runtime.MarshalAsJSON(req, body); err != nil {
+	if err := setRequestBody(req, body); err != nil {
 		return nil, err
 	}
 	resp, err := client.pl.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent) {
 		return nil, runtime.NewResponseError(resp)
 	}
 
-	// TODO: Handle long-running operations if needed
-
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
+	if isLongRunningOperation(resp, options) {
+		return client.pollLongRunningOperation(ctx, resp, apiVersion, false)
 	}
-	return responseBody, nil
+
+	return unmarshalResponseBody(resp)
 }
 
 func (client *MSGraphClient) Delete(ctx context.Context, url string, apiVersion string, options RequestOptions) error {
 	if options.RetryOptions != nil {
-		ctx = policy.WithRetryOptions(ctx, *options.RetryOptions)
+		ctx = withRetryOptions(ctx, options.RetryOptions)
 	}
 	req, err := runtime.NewRequest(ctx, http.MethodDelete, runtime.JoinPaths(client.host, apiVersion, url))
 	if err != nil {
@@ -270,18 +387,24 @@ func (client *MSGraphClient) Delete(ctx context.Context, url string, apiVersion
 		return err
 	}
 
-	// TODO: Handle long-running operations if needed
-
 	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
 		return runtime.NewResponseError(resp)
 	}
+
+	if isLongRunningOperation(resp, options) {
+		_, err = client.pollLongRunningOperation(ctx, resp, apiVersion, false)
+		return err
+	}
 	return nil
 }
 
 func (client *MSGraphClient) Action(ctx context.Context, method string, url string, apiVersion string, body interface{}, options RequestOptions) (interface{}, error) {
+	if method == http.MethodGet {
+		options = withConsistencyLevel(options)
+	}
 	// apply per-request retry options via context
 	if options.RetryOptions != nil {
-		ctx = policy.WithRetryOptions(ctx, *options.RetryOptions)
+		ctx = withRetryOptions(ctx, options.RetryOptions)
 	}
 
 	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(client.host, apiVersion, url))
@@ -301,10 +424,9 @@ func (client *MSGraphClient) Action(ctx context.Context, method string, url stri
 
 	// Set request body if provided
 	if body != nil {
-		if err := runtime.MarshalAsJSON(req, body); err != nil {
+		if err := setRequestBody(req, body); err != nil {
 			return nil, err
 		}
-		req.Raw().Header.Set("Content-Type", "application/json")
 	}
 
 	resp, err := client.pl.Do(req)
@@ -317,17 +439,16 @@ func (client *MSGraphClient) Action(ctx context.Context, method string, url stri
 		return nil, runtime.NewResponseError(resp)
 	}
 
+	if isLongRunningOperation(resp, options) {
+		return client.pollLongRunningOperation(ctx, resp, apiVersion, false)
+	}
+
 	// For methods that typically don't return a body (like DELETE), or if response is empty
 	if resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 {
 		return nil, nil
 	}
 
-	var responseBody interface{}
-	if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
-		return nil, err
-	}
-
-	return responseBody, nil
+	return unmarshalResponseBody(resp)
 }
 
 func (client *MSGraphClient) GraphBaseUrl() string {
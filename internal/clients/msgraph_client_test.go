@@ -0,0 +1,343 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// newPagedServer returns a client of a server which serves a collection of 3 pages with 2 items each, and counts the requests.
+// When the `$top` query parameter covers all items, the collection is served in a single page.
+// The `$top` query parameter of the first page is recorded in top.
+func newPagedServer(t *testing.T, requests *int32, top *string) (*MSGraphClient, *string) {
+	var serverUrl string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if top != nil && r.URL.Query().Get("page") == "" {
+			*top = r.URL.Query().Get("$top")
+		}
+		if v, _ := strconv.Atoi(r.URL.Query().Get("$top")); v >= 6 {
+			_, _ = fmt.Fprint(w, `{"@odata.context":"ctx","@odata.count":6,"value":[{"id":"0"},{"id":"1"},{"id":"2"},{"id":"3"},{"id":"4"},{"id":"5"}]}`)
+			return
+		}
+		page := 0
+		if v := r.URL.Query().Get("page"); v != "" {
+			_, _ = fmt.Sscanf(v, "%d", &page)
+		}
+		nextLink := ""
+		if page < 2 {
+			nextLink = fmt.Sprintf(`,"@odata.nextLink":"%s/v1.0/users?page=%d"`, serverUrl, page+1)
+		}
+		_, _ = fmt.Fprintf(w, `{"@odata.context":"ctx","@odata.count":6,"value":[{"id":"%d"},{"id":"%d"}]%s}`, page*2, page*2+1, nextLink)
+	}))
+	serverUrl = client.GraphBaseUrl()
+	return client, &serverUrl
+}
+
+func TestReadWithPaging(t *testing.T) {
+	testcases := []struct {
+		Name              string
+		Paging            PagingOptions
+		QueryParameters   map[string]string
+		ExpectedValue     string
+		ExpectedTruncated bool
+		ExpectedRequests  int32
+		ExpectedTop       string
+	}{
+		{
+			Name:             "all pages",
+			ExpectedValue:    "[map[id:0] map[id:1] map[id:2] map[id:3] map[id:4] map[id:5]]",
+			ExpectedRequests: 3,
+		},
+		{
+			Name:              "max pages",
+			Paging:            PagingOptions{MaxPages: 2},
+			ExpectedValue:     "[map[id:0] map[id:1] map[id:2] map[id:3]]",
+			ExpectedTruncated: true,
+			ExpectedRequests:  2,
+		},
+		{
+			Name:              "max pages of the last page",
+			Paging:            PagingOptions{MaxPages: 3},
+			ExpectedValue:     "[map[id:0] map[id:1] map[id:2] map[id:3] map[id:4] map[id:5]]",
+			ExpectedTruncated: false,
+			ExpectedRequests:  3,
+		},
+		{
+			Name:              "max items in the middle of a page",
+			Paging:            PagingOptions{MaxItems: 3},
+			ExpectedValue:     "[map[id:0] map[id:1] map[id:2]]",
+			ExpectedTruncated: true,
+			ExpectedRequests:  2,
+		},
+		{
+			Name:              "max items at the end of a page",
+			Paging:            PagingOptions{MaxItems: 2},
+			ExpectedValue:     "[map[id:0] map[id:1]]",
+			ExpectedTruncated: true,
+			ExpectedRequests:  1,
+		},
+		{
+			Name:              "max items of all items",
+			Paging:            PagingOptions{MaxItems: 6},
+			ExpectedValue:     "[map[id:0] map[id:1] map[id:2] map[id:3] map[id:4] map[id:5]]",
+			ExpectedTruncated: false,
+			ExpectedRequests:  3,
+		},
+		{
+			Name:             "page size",
+			Paging:           PagingOptions{PageSize: 2},
+			ExpectedValue:    "[map[id:0] map[id:1] map[id:2] map[id:3] map[id:4] map[id:5]]",
+			ExpectedRequests: 3,
+			ExpectedTop:      "2",
+		},
+		{
+			Name:             "page size doesn't override $top",
+			Paging:           PagingOptions{PageSize: 2},
+			QueryParameters:  map[string]string{"$top": "5"},
+			ExpectedValue:    "[map[id:0] map[id:1] map[id:2] map[id:3] map[id:4] map[id:5]]",
+			ExpectedRequests: 3,
+			ExpectedTop:      "5",
+		},
+		{
+			Name:              "max items within a single page",
+			Paging:            PagingOptions{MaxItems: 4, PageSize: 10},
+			ExpectedValue:     "[map[id:0] map[id:1] map[id:2] map[id:3]]",
+			ExpectedTruncated: true,
+			ExpectedRequests:  1,
+			ExpectedTop:       "10",
+		},
+		{
+			Name:             "max items of all items within a single page",
+			Paging:           PagingOptions{MaxItems: 6, PageSize: 10},
+			ExpectedValue:    "[map[id:0] map[id:1] map[id:2] map[id:3] map[id:4] map[id:5]]",
+			ExpectedRequests: 1,
+			ExpectedTop:      "10",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var requests int32
+			var top string
+			client, _ := newPagedServer(t, &requests, &top)
+
+			options := RequestOptions{QueryParameters: tc.QueryParameters}
+			body, truncated, err := client.ReadWithPaging(context.Background(), "/users", "v1.0", options, tc.Paging)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			bodyMap := body.(map[string]interface{})
+			if actual := fmt.Sprint(bodyMap["value"]); actual != tc.ExpectedValue {
+				t.Fatalf("expected value %s, got %s", tc.ExpectedValue, actual)
+			}
+			if truncated != tc.ExpectedTruncated {
+				t.Fatalf("expected truncated %v, got %v", tc.ExpectedTruncated, truncated)
+			}
+			if requests != tc.ExpectedRequests {
+				t.Fatalf("expected %d requests, got %d", tc.ExpectedRequests, requests)
+			}
+			// only the @odata.count is kept, the other properties of the pages don't describe the merged collection
+			if _, ok := bodyMap["@odata.context"]; requests > 1 && (ok || bodyMap["@odata.count"] != float64(6) || len(bodyMap) != 2) {
+				t.Fatalf("expected only the value and the @odata.count to be kept, got %v", bodyMap)
+			}
+			if tc.ExpectedTop != "" && top != tc.ExpectedTop {
+				t.Fatalf("expected $top %s, got %v", tc.ExpectedTop, top)
+			}
+			if _, ok := options.QueryParameters["$top"]; ok && tc.QueryParameters == nil {
+				t.Fatalf("the query parameters of the caller must not be modified")
+			}
+		})
+	}
+}
+
+func TestFollowNextLinks(t *testing.T) {
+	var requests int32
+	client, serverUrl := newPagedServer(t, &requests, nil)
+
+	firstPage := map[string]interface{}{
+		"value":           []interface{}{"a"},
+		"@odata.nextLink": fmt.Sprintf("%s/v1.0/users?page=1", *serverUrl),
+	}
+	body, truncated, err := client.FollowNextLinks(context.Background(), firstPage, RequestOptions{}, PagingOptions{MaxPages: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := fmt.Sprint(body.(map[string]interface{})["value"]); actual != "[a map[id:2] map[id:3]]" || !truncated || requests != 1 {
+		t.Fatalf("unexpected result: %s, truncated %v, %d requests", actual, truncated, requests)
+	}
+
+	body, truncated, err = client.FollowNextLinks(context.Background(), map[string]interface{}{"id": "1"}, RequestOptions{}, PagingOptions{MaxPages: 2})
+	if err != nil || truncated || fmt.Sprint(body) != "map[id:1]" {
+		t.Fatalf("unexpected result: %v, truncated %v, %v", body, truncated, err)
+	}
+}
+
+func TestIsAdvancedQuery(t *testing.T) {
+	testcases := []struct {
+		QueryParameters map[string]string
+		Expected        bool
+	}{
+		{QueryParameters: nil, Expected: false},
+		{QueryParameters: map[string]string{"$select": "id", "$top": "5"}, Expected: false},
+		{QueryParameters: map[string]string{"$search": `"displayName:foo"`}, Expected: true},
+		{QueryParameters: map[string]string{"$count": "true"}, Expected: true},
+		{QueryParameters: map[string]string{"$count": "false"}, Expected: false},
+		{QueryParameters: map[string]string{"$filter": "startsWith(displayName,'a')"}, Expected: false},
+		{QueryParameters: map[string]string{"$filter": "endsWith(mail,'@contoso.com')"}, Expected: true},
+		{QueryParameters: map[string]string{"$filter": "companyName ne null"}, Expected: true},
+		{QueryParameters: map[string]string{"$filter": "NOT groupTypes/any(c:c eq 'Unified')"}, Expected: true},
+		{QueryParameters: map[string]string{"$filter": "not(startsWith(displayName,'a'))"}, Expected: true},
+		{QueryParameters: map[string]string{"$filter": "displayName eq 'notes'"}, Expected: false},
+		{QueryParameters: map[string]string{"$filter": "assignedLicenses/$count eq 0"}, Expected: true},
+		{QueryParameters: map[string]string{"$orderby": "displayName"}, Expected: false},
+		{QueryParameters: map[string]string{"$orderby": "displayName", "$filter": "accountEnabled eq true"}, Expected: true},
+	}
+
+	for _, tc := range testcases {
+		if actual := IsAdvancedQuery(tc.QueryParameters); actual != tc.Expected {
+			t.Errorf("expected %v for %v, got %v", tc.Expected, tc.QueryParameters, actual)
+		}
+	}
+}
+
+func TestReadWithPaging_AdvancedQuery(t *testing.T) {
+	var serverUrl string
+	consistencyLevels := make([]string, 0)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		consistencyLevels = append(consistencyLevels, r.Header.Get("ConsistencyLevel"))
+		if r.URL.Query().Get("page") == "" {
+			_, _ = fmt.Fprintf(w, `{"@odata.count":3,"value":[{"id":"0"},{"id":"1"}],"@odata.nextLink":"%s/v1.0/users?page=1"}`, serverUrl)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"value":[{"id":"2"}]}`)
+	}))
+	serverUrl = client.GraphBaseUrl()
+
+	options := RequestOptions{QueryParameters: map[string]string{"$count": "true"}}
+	body, _, err := client.ReadWithPaging(context.Background(), "/users", "v1.0", options, PagingOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bodyMap := body.(map[string]interface{})
+	if bodyMap["@odata.count"] != float64(3) || len(bodyMap["value"].([]interface{})) != 3 {
+		t.Fatalf("expected the @odata.count and all items, got %v", bodyMap)
+	}
+	if fmt.Sprint(consistencyLevels) != "[eventual eventual]" {
+		t.Fatalf("expected the ConsistencyLevel header in all requests, got %v", consistencyLevels)
+	}
+	if options.Headers != nil {
+		t.Fatalf("the headers of the caller must not be modified")
+	}
+
+	consistencyLevels = consistencyLevels[:0]
+	options = RequestOptions{Headers: map[string]string{"consistencylevel": "session"}, QueryParameters: map[string]string{"$search": `"displayName:a"`}}
+	if _, _, err := client.ReadWithPaging(context.Background(), "/users", "v1.0", options, PagingOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(consistencyLevels) != "[session session]" {
+		t.Fatalf("expected the configured ConsistencyLevel header to be kept, got %v", consistencyLevels)
+	}
+}
+
+func TestCreateUpdate_EmptyResponse(t *testing.T) {
+	testcases := []struct {
+		Name       string
+		StatusCode int
+		Body       string
+		Expected   interface{}
+	}{
+		{Name: "204 No Content", StatusCode: http.StatusNoContent, Expected: nil},
+		{Name: "201 Created without body", StatusCode: http.StatusCreated, Expected: nil},
+		{Name: "200 OK without body", StatusCode: http.StatusOK, Expected: nil},
+		{Name: "200 OK with body", StatusCode: http.StatusOK, Body: `{"id":"1"}`, Expected: "map[id:1]"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.StatusCode)
+				_, _ = fmt.Fprint(w, tc.Body)
+			}))
+
+			created, err := client.Create(context.Background(), "/applications", "v1.0", map[string]interface{}{}, RequestOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			updated, err := client.Update(context.Background(), "/applications/1", "v1.0", map[string]interface{}{}, RequestOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, actual := range []interface{}{created, updated} {
+				if tc.Expected == nil && actual != nil || tc.Expected != nil && fmt.Sprint(actual) != tc.Expected {
+					t.Fatalf("expected %v, got %v", tc.Expected, actual)
+				}
+			}
+		})
+	}
+}
+
+// headerPolicy sets a header in the requests, like the default headers of the provider.
+type headerPolicy struct{}
+
+func (headerPolicy) Do(req *policy.Request) (*http.Response, error) {
+	req.Raw().Header.Set("X-Default-Header", "value")
+	return req.Next()
+}
+
+// countingTransport counts the requests sent with the transport of the client.
+type countingTransport struct {
+	requests int32
+}
+
+func (t *countingTransport) Do(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	return http.DefaultClient.Do(req)
+}
+
+func TestNewMSGraphClient_UnauthenticatedPipeline(t *testing.T) {
+	var header atomic.Value
+	header.Store("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header.Store(r.Header.Get("X-Default-Header") + r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	transport := &countingTransport{}
+	client, err := NewMSGraphClient(fakeCredential{}, &policy.ClientOptions{
+		Cloud: cloud.Configuration{
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				ServiceNameMSGraph: {Endpoint: "https://graph.microsoft.com", Audience: "https://graph.microsoft.com"},
+			},
+		},
+		PerCallPolicies: []policy.Policy{headerPolicy{}},
+		Retry:           policy.RetryOptions{MaxRetries: -1},
+		Transport:       transport,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL+"/upload")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.unauthenticatedPl.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := header.Load().(string); v != "" {
+		t.Fatalf("the policies of the client were applied to the request to another host: %q", v)
+	}
+	if transport.requests != 1 {
+		t.Fatalf("expected the request to be sent with the transport of the client, got %d requests", transport.requests)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,9 +32,92 @@ type RequestOptions struct {
 	Headers         map[string]string
 	QueryParameters map[string]string
 	RetryOptions    *policy.RetryOptions
+	// PollLongRunningOperation enables polling the operation referenced by a 202 Accepted response.
+	PollLongRunningOperation bool
 }
 
-// CombineRetryOptions combines multiple RequestOptions into a single policy.RetryOptions.
+// PagingOptions bounds the pages fetched from a collection, the zero value fetches all pages.
+type PagingOptions struct {
+	// MaxItems is the maximum number of items returned, 0 means unlimited.
+	MaxItems int
+	// MaxPages is the maximum number of pages fetched, 0 means unlimited.
+	MaxPages int
+	// PageSize is the number of items requested per page via the `$top` query parameter, 0 means the server default.
+	PageSize int
+}
+
+// Apply returns a copy of the request options with the `$top` query parameter set to the page size,
+// unless the page size is not specified or the query parameter is already set.
+func (o PagingOptions) Apply(options RequestOptions) RequestOptions {
+	if o.PageSize <= 0 {
+		return options
+	}
+	queryParameters := make(map[string]string, len(options.QueryParameters)+1)
+	for key, value := range options.QueryParameters {
+		if strings.EqualFold(key, "$top") {
+			return options
+		}
+		queryParameters[key] = value
+	}
+	queryParameters["$top"] = strconv.Itoa(o.PageSize)
+	options.QueryParameters = queryParameters
+	return options
+}
+
+const (
+	consistencyLevelHeader   = "ConsistencyLevel"
+	consistencyLevelEventual = "eventual"
+)
+
+// advancedQueryFilterRegex matches the `$filter` operators which are only supported by the advanced queries on directory objects,
+// e.g. `endsWith(mail,'@contoso.com')`, `displayName ne null`, `NOT groupTypes/any(c:c eq 'Unified')` and `assignedLicenses/$count eq 0`.
+var advancedQueryFilterRegex = regexp.MustCompile(`(?i)\bendswith\s*\(|\sne\s|\bnot\s*\(|(^|[\s(])not\s|/\$count\b`)
+
+// IsAdvancedQuery returns whether the query parameters form an advanced query, which requires the `ConsistencyLevel: eventual` header:
+// `$search`, `$count=true`, the `endsWith`, `ne`, `not` and `$count` operators in `$filter`, or `$orderby` combined with `$filter`.
+func IsAdvancedQuery(queryParameters map[string]string) bool {
+	hasFilter, hasOrderBy := false, false
+	for key, value := range queryParameters {
+		switch strings.ToLower(key) {
+		case "$search":
+			return true
+		case "$count":
+			if strings.EqualFold(strings.TrimSpace(value), "true") {
+				return true
+			}
+		case "$filter":
+			if advancedQueryFilterRegex.MatchString(value) {
+				return true
+			}
+			hasFilter = true
+		case "$orderby":
+			hasOrderBy = true
+		}
+	}
+	return hasFilter && hasOrderBy
+}
+
+// withConsistencyLevel returns a copy of the request options with the `ConsistencyLevel: eventual` header if the query
+// parameters form an advanced query, unless the header is already set.
+func withConsistencyLevel(options RequestOptions) RequestOptions {
+	if !IsAdvancedQuery(options.QueryParameters) {
+		return options
+	}
+	headers := make(map[string]string, len(options.Headers)+1)
+	for key, value := range options.Headers {
+		if strings.EqualFold(key, consistencyLevelHeader) {
+			return options
+		}
+		headers[key] = value
+	}
+	headers[consistencyLevelHeader] = consistencyLevelEventual
+	options.Headers = headers
+	return options
+}
+
+// CombineRetryOptions combines multiple RequestOptions into a single policy.RetryOptions. A request is retried if any of the
+// options retries it, the maximum attempts and the backoff of each options are applied by its ShouldRetry function.
+// If any of the options is created by NewRetryOptions, the other options keep their delay by a backoff.
 func CombineRetryOptions(opts ...*policy.RetryOptions) *policy.RetryOptions {
 	if len(opts) == 0 {
 		return nil
@@ -63,8 +148,16 @@ func CombineRetryOptions(opts ...*policy.RetryOptions) *policy.RetryOptions {
 		}
 	}
 
+	backoffDelay := false
+	for _, opt := range opts {
+		if hasBackoffDelay(opt) {
+			backoffDelay = true
+		}
+	}
+
 	var retryDelay time.Duration = 0
 	var maxRetryDelay time.Duration = 0
+	shouldRetries := make([]func(*http.Response, error) bool, 0, len(opts))
 	for _, opt := range opts {
 		if opt == nil {
 			continue
@@ -75,29 +168,49 @@ func CombineRetryOptions(opts ...*policy.RetryOptions) *policy.RetryOptions {
 		if opt.MaxRetryDelay > maxRetryDelay {
 			maxRetryDelay = opt.MaxRetryDelay
 		}
+		if opt.ShouldRetry == nil {
+			continue
+		}
+		if backoffDelay && !hasBackoffDelay(opt) {
+			// the combined options are applied by the backoffRetryPolicy, so the options keep their own delay by a backoff
+			backoff := retryBackoff{InitialDelay: opt.RetryDelay, MaxDelay: opt.MaxRetryDelay}
+			if backoff.InitialDelay == 0 {
+				backoff.InitialDelay = defaultRetryDelay
+			}
+			if backoff.MaxDelay == 0 {
+				backoff.MaxDelay = defaultMaxRetryDelay
+			}
+			shouldRetries = append(shouldRetries, backoff.wrap(opt.ShouldRetry))
+			continue
+		}
+		shouldRetries = append(shouldRetries, opt.ShouldRetry)
 	}
 
 	shouldRetry := func(resp *http.Response, err error) bool {
-		for _, opt := range opts {
-			if opt == nil || opt.ShouldRetry == nil {
-				continue
-			}
-			if opt.ShouldRetry(resp, err) {
+		for _, f := range shouldRetries {
+			if f(resp, err) {
 				return true
 			}
 		}
 		return false
 	}
 
-	return &policy.RetryOptions{
+	options := &policy.RetryOptions{
 		MaxRetries:    maxRetries,
 		RetryDelay:    retryDelay,
 		MaxRetryDelay: maxRetryDelay,
 		StatusCodes:   statusCodes,
 		ShouldRetry:   shouldRetry,
 	}
+	if backoffDelay {
+		withBackoffDelay(options)
+	}
+	return options
 }
 
+// unboundedMaxRetries is the maximum retries of the options which retry until the context deadline.
+const unboundedMaxRetries = math.MaxInt16
+
 // NewRetryOptionsForReadAfterCreate creates a RetryOptions for read-after-create operations.
 func NewRetryOptionsForReadAfterCreate() *policy.RetryOptions {
 	log.Printf("[DEBUG] Using custom retry configuration for read after create")
@@ -107,7 +220,7 @@ func NewRetryOptionsForReadAfterCreate() *policy.RetryOptions {
 	statusCodes = append(statusCodes, DefaultRetryableReadAfterCreateStatusCodes...)
 	return &policy.RetryOptions{
 		// Set a very high max retries to make sure context deadline is respected.
-		MaxRetries:  math.MaxInt16,
+		MaxRetries:  unboundedMaxRetries,
 		StatusCodes: statusCodes,
 		ShouldRetry: func(resp *http.Response, err error) bool {
 			// We need to test for status codes here too. This covers the case that these options are combined with
@@ -127,20 +240,78 @@ func NewRetryOptions(rtry retry.Value) *policy.RetryOptions {
 	if rtry.IsNull() || rtry.IsUnknown() {
 		return nil
 	}
+	options := newRetryOptions(rtry.GetErrorMessagesRegex(), rtry.GetStatusCodes(), rtry.GetResponseIsRetryable())
+	backoff := retryBackoff{
+		MaxAttempts:  rtry.GetMaxAttempts(),
+		InitialDelay: rtry.GetInitialDelay(),
+		MaxDelay:     rtry.GetMaxDelay(),
+		Multiplier:   rtry.GetMultiplier(),
+	}
+	switch {
+	case backoff.MaxAttempts == 1:
+		// a negative value disables the retries of azcore
+		options.MaxRetries = -1
+	case backoff.MaxAttempts > 1:
+		options.MaxRetries = int32(backoff.MaxAttempts - 1)
+	}
+	if backoff.InitialDelay == 0 {
+		backoff.InitialDelay = defaultRetryDelay
+	}
+	if backoff.MaxDelay == 0 {
+		backoff.MaxDelay = defaultMaxRetryDelay
+	}
+	options.ShouldRetry = backoff.wrap(options.ShouldRetry)
+	withBackoffDelay(options)
+	return options
+}
+
+// withBackoffDelay marks the retry options to be applied by the backoffRetryPolicy instead of the retry policy of azcore.
+func withBackoffDelay(options *policy.RetryOptions) {
+	options.RetryDelay = -1
+	options.MaxRetryDelay = -1
+}
+
+// hasBackoffDelay returns whether the retry options are applied by the backoffRetryPolicy.
+func hasBackoffDelay(options *policy.RetryOptions) bool {
+	return options != nil && options.RetryDelay < 0
+}
+
+const (
+	// defaultRetryDelay and defaultMaxRetryDelay are the defaults of azcore.
+	defaultRetryDelay    = 800 * time.Millisecond
+	defaultMaxRetryDelay = 60 * time.Second
+)
+
+// newRetryOptionsForErrorMessages creates a RetryOptions which retries the requests failing with an error message matching any of the regexps.
+func newRetryOptionsForErrorMessages(regexps []regexp.Regexp) *policy.RetryOptions {
+	return newRetryOptions(regexps, nil, "")
+}
 
+// newRetryOptions creates a RetryOptions which retries the requests failing with the default retryable status codes, one of the
+// extra status codes, an error message matching any of the regexps, or an error response matching the JMESPath expression.
+func newRetryOptions(regexps []regexp.Regexp, extraStatusCodes []int, expression string) *policy.RetryOptions {
 	log.Printf("[DEBUG] Using custom retry configuration")
+	statusCodes := make([]int, 0, len(DefaultRetryableStatusCodes)+len(extraStatusCodes))
+	statusCodes = append(statusCodes, DefaultRetryableStatusCodes...)
+	statusCodes = append(statusCodes, extraStatusCodes...)
 	return &policy.RetryOptions{
 		// Set a very high max retries to make sure context deadline is respected.
-		MaxRetries:  math.MaxInt16,
-		StatusCodes: DefaultRetryableStatusCodes,
+		MaxRetries:  unboundedMaxRetries,
+		StatusCodes: statusCodes,
 		ShouldRetry: func(resp *http.Response, err error) bool {
-			// We need to test for DefaultRetryableStatusCodes here as using ShouldRetry overrides the use of StatusCodes.
-			for _, code := range DefaultRetryableStatusCodes {
-				if resp.StatusCode == code {
-					return true
+			// We need to test for the status codes here as using ShouldRetry overrides the use of StatusCodes.
+			if resp != nil {
+				for _, code := range statusCodes {
+					if resp.StatusCode == code {
+						return true
+					}
 				}
 			}
 
+			if expression != "" && responseIsRetryable(resp, expression) {
+				return true
+			}
+
 			// Get the error message to check against regex patterns,
 			// If use the err.Error() string first, else get the response error from the HTTP response.
 			var errorMsg string
@@ -156,7 +327,7 @@ func NewRetryOptions(rtry retry.Value) *policy.RetryOptions {
 			if errorMsg == "" {
 				return false
 			}
-			for _, re := range rtry.GetErrorMessagesRegex() {
+			for _, re := range regexps {
 				if re.MatchString(errorMsg) {
 					log.Printf("[DEBUG] Retrying request due to error: %s matches regex %s", errorMsg, re.String())
 					return true
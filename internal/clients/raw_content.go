@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+const defaultRawContentType = "application/octet-stream"
+
+// RawContent is a request or response body which is not JSON, e.g. the `$value` of a user's photo or of a drive item.
+type RawContent struct {
+	ContentType string
+	Data        []byte
+}
+
+// NewRawContentFromBase64 returns the raw content of the base64 encoded data, the content type defaults to `application/octet-stream`.
+func NewRawContentFromBase64(data string, contentType string) (*RawContent, error) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	if contentType == "" {
+		contentType = defaultRawContentType
+	}
+	return &RawContent{
+		ContentType: contentType,
+		Data:        decoded,
+	}, nil
+}
+
+// Base64 returns the base64 encoded data.
+func (c *RawContent) Base64() string {
+	return base64.StdEncoding.EncodeToString(c.Data)
+}
+
+// Hash returns the hex encoded SHA-256 hash of the data.
+func (c *RawContent) Hash() string {
+	hash := sha256.Sum256(c.Data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Properties returns the content as a JSON object, so the values can be exported via `response_export_values`.
+func (c *RawContent) Properties() map[string]interface{} {
+	return map[string]interface{}{
+		"content_base64": c.Base64(),
+		"content_type":   c.ContentType,
+		"content_sha256": c.Hash(),
+	}
+}
+
+// isJSONContentType returns whether the content type is JSON, e.g. `application/json;odata.metadata=minimal`.
+// An empty content type is treated as JSON, because it's the default of Microsoft Graph.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// setRequestBody sets the body of the request, raw content is sent as it is, other values are marshalled as JSON.
+func setRequestBody(req *policy.Request, body interface{}) error {
+	if content, ok := body.(*RawContent); ok {
+		contentType := content.ContentType
+		if contentType == "" {
+			contentType = defaultRawContentType
+		}
+		return req.SetBody(streaming.NopCloser(bytes.NewReader(content.Data)), contentType)
+	}
+	return runtime.MarshalAsJSON(req, body)
+}
+
+// unmarshalResponseBody returns the body of the response, a body which is not JSON is returned as raw content. A JSON object
+// or array labelled with another content type, e.g. `text/plain`, is still unmarshalled, as it was before raw content was supported.
+func unmarshalResponseBody(resp *http.Response) (interface{}, error) {
+	contentType := resp.Header.Get("Content-Type")
+	data, err := runtime.Payload(resp)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	if isJSONContentType(contentType) || isJSONDocument(data) {
+		var responseBody interface{}
+		if err := runtime.UnmarshalAsJSON(resp, &responseBody); err != nil {
+			return nil, err
+		}
+		return responseBody, nil
+	}
+	return &RawContent{
+		ContentType: contentType,
+		Data:        data,
+	}, nil
+}
+
+func isJSONDocument(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		return false
+	}
+	return json.Valid(data)
+}
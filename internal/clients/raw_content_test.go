@@ -0,0 +1,89 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestIsJSONContentType(t *testing.T) {
+	testcases := []struct {
+		ContentType string
+		Expected    bool
+	}{
+		{ContentType: "", Expected: true},
+		{ContentType: "application/json", Expected: true},
+		{ContentType: "application/json;odata.metadata=minimal;odata.streaming=true;IEEE754Compatible=false;charset=utf-8", Expected: true},
+		{ContentType: "application/problem+json", Expected: true},
+		{ContentType: "image/jpeg", Expected: false},
+		{ContentType: "text/plain; charset=utf-8", Expected: false},
+		{ContentType: "application/octet-stream", Expected: false},
+		{ContentType: "not a content type;", Expected: false},
+	}
+
+	for _, tc := range testcases {
+		if actual := isJSONContentType(tc.ContentType); actual != tc.Expected {
+			t.Errorf("expected %v for %q, got %v", tc.Expected, tc.ContentType, actual)
+		}
+	}
+}
+
+func TestNewRawContentFromBase64(t *testing.T) {
+	content, err := NewRawContentFromBase64("aGVsbG8=", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content.Data) != "hello" || content.ContentType != "application/octet-stream" {
+		t.Fatalf("unexpected content: %+v", content)
+	}
+	if content.Base64() != "aGVsbG8=" {
+		t.Fatalf("unexpected base64: %s", content.Base64())
+	}
+	if content.Hash() != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("unexpected hash: %s", content.Hash())
+	}
+
+	if _, err := NewRawContentFromBase64("hello!", ""); err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+}
+
+func TestAction_RawContent(t *testing.T) {
+	var requestContentType, requestBody string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			data, _ := io.ReadAll(r.Body)
+			requestContentType, requestBody = r.Header.Get("Content-Type"), string(data)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png"))
+	}))
+
+	content := &RawContent{ContentType: "image/png", Data: []byte("png")}
+	responseBody, err := client.Action(context.Background(), http.MethodPut, "/users/1/photo/$value", "v1.0", content, RequestOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if responseBody != nil || requestContentType != "image/png" || requestBody != "png" {
+		t.Fatalf("unexpected request: %s %q, response: %v", requestContentType, requestBody, responseBody)
+	}
+
+	responseBody, err = client.Action(context.Background(), http.MethodGet, "/users/1/photo/$value", "v1.0", nil, RequestOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if raw, ok := responseBody.(*RawContent); !ok || raw.ContentType != "image/png" || string(raw.Data) != "png" {
+		t.Fatalf("expected the raw content, got %v", responseBody)
+	}
+
+	raw, err := client.ReadRaw(context.Background(), "/users/1/photo/$value", "v1.0", RequestOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if raw.ContentType != "image/png" || string(raw.Data) != "png" {
+		t.Fatalf("unexpected raw content: %+v", raw)
+	}
+}
@@ -0,0 +1,137 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// defaultRedactedPaths are the JSON paths of the known sensitive properties in Microsoft Graph requests and responses.
+// A path matches a property if it's a suffix of the property's path, the array indexes are not part of the path,
+// e.g. `secretText` matches `passwordCredentials[0].secretText` and the nested `body.secretText` in a JSON batch response.
+var defaultRedactedPaths = []string{
+	"secretText",
+	"passwordProfile.password",
+	"newPassword",
+	"currentPassword",
+	"keyCredential.key",
+	"keyCredentials.key",
+	"proof",
+	"clientSecret",
+	"accessToken",
+	"refreshToken",
+	"privateKey",
+}
+
+// defaultRedactedHeaders are the names of the headers which contain credentials.
+var defaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Redactor replaces the values of the sensitive headers and JSON properties with `REDACTED`.
+type Redactor struct {
+	paths   [][]string
+	headers map[string]bool
+}
+
+// NewRedactor returns a Redactor which redacts the built-in sensitive headers and JSON paths,
+// together with the extra ones. The paths are dot separated property names, e.g. `passwordProfile.password`.
+func NewRedactor(extraPaths []string, extraHeaders []string) *Redactor {
+	r := &Redactor{
+		paths:   make([][]string, 0),
+		headers: make(map[string]bool),
+	}
+	for _, path := range append(append([]string{}, defaultRedactedPaths...), extraPaths...) {
+		if path = strings.Trim(path, ". "); path != "" {
+			r.paths = append(r.paths, strings.Split(path, "."))
+		}
+	}
+	for _, header := range append(append([]string{}, defaultRedactedHeaders...), extraHeaders...) {
+		r.headers[strings.ToLower(header)] = true
+	}
+	return r
+}
+
+// RedactHeaders returns the headers as a map of comma separated values, with the sensitive ones redacted.
+func (r *Redactor) RedactHeaders(input http.Header) map[string]string {
+	output := make(map[string]string)
+	for k, v := range input {
+		if r.headers[strings.ToLower(k)] {
+			output[k] = redactedValue
+		} else {
+			output[k] = strings.Join(v, ",")
+		}
+	}
+	return output
+}
+
+// RedactBody returns the body with the values of the sensitive JSON properties redacted.
+// The body is returned unchanged if it's not JSON or doesn't contain any sensitive property.
+func (r *Redactor) RedactBody(body string) string {
+	if body == "" {
+		return body
+	}
+	var input interface{}
+	if err := json.Unmarshal([]byte(body), &input); err != nil {
+		return body
+	}
+	output, redacted := r.redactValue(nil, input)
+	if !redacted {
+		return body
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return redactedValue
+	}
+	return string(data)
+}
+
+func (r *Redactor) redactValue(path []string, input interface{}) (interface{}, bool) {
+	redacted := false
+	switch v := input.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			propertyPath := append(append(make([]string, 0, len(path)+1), path...), key)
+			if r.matches(propertyPath) {
+				v[key] = redactedValue
+				redacted = true
+				continue
+			}
+			if output, ok := r.redactValue(propertyPath, value); ok {
+				v[key] = output
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			if output, ok := r.redactValue(path, value); ok {
+				v[i] = output
+				redacted = true
+			}
+		}
+	}
+	return input, redacted
+}
+
+func (r *Redactor) matches(path []string) bool {
+	for _, pattern := range r.paths {
+		if len(pattern) > len(path) {
+			continue
+		}
+		offset := len(path) - len(pattern)
+		matched := true
+		for i := range pattern {
+			if !strings.EqualFold(pattern[i], path[offset+i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package clients
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedactor_RedactBody(t *testing.T) {
+	testcases := []struct {
+		Name         string
+		ExtraPaths   []string
+		Input        string
+		Expected     string
+		ExpectedSame bool
+	}{
+		{
+			Name:     "secret text of a password credential",
+			Input:    `{"displayName":"test","secretText":"abc","keyId":"1"}`,
+			Expected: `{"displayName":"test","keyId":"1","secretText":"REDACTED"}`,
+		},
+		{
+			Name:     "password of a user",
+			Input:    `{"displayName":"test","passwordProfile":{"password":"abc","forceChangePasswordNextSignIn":true}}`,
+			Expected: `{"displayName":"test","passwordProfile":{"forceChangePasswordNextSignIn":true,"password":"REDACTED"}}`,
+		},
+		{
+			Name:     "keys in an array",
+			Input:    `{"keyCredentials":[{"key":"abc","type":"AsymmetricX509Cert"},{"key":"def"}]}`,
+			Expected: `{"keyCredentials":[{"key":"REDACTED","type":"AsymmetricX509Cert"},{"key":"REDACTED"}]}`,
+		},
+		{
+			Name:     "nested in a batch response",
+			Input:    `{"responses":[{"id":"1","status":200,"body":{"secretText":"abc"}}]}`,
+			Expected: `{"responses":[{"body":{"secretText":"REDACTED"},"id":"1","status":200}]}`,
+		},
+		{
+			Name:     "case insensitive",
+			Input:    `{"PasswordProfile":{"Password":"abc"}}`,
+			Expected: `{"PasswordProfile":{"Password":"REDACTED"}}`,
+		},
+		{
+			Name:         "a key which is not a key credential",
+			Input:        `{"key":"abc","value":"def"}`,
+			ExpectedSame: true,
+		},
+		{
+			Name:       "extra paths",
+			ExtraPaths: []string{"secrets.value", " apiKey "},
+			Input:      `{"secrets":[{"key":"token","value":"abc"}],"apiKey":"def","value":"ghi"}`,
+			Expected:   `{"apiKey":"REDACTED","secrets":[{"key":"token","value":"REDACTED"}],"value":"ghi"}`,
+		},
+		{
+			Name:         "no sensitive property",
+			Input:        `{ "displayName": "test" }`,
+			ExpectedSame: true,
+		},
+		{
+			Name:         "not json",
+			Input:        `secretText=abc`,
+			ExpectedSame: true,
+		},
+		{
+			Name:         "empty",
+			Input:        ``,
+			ExpectedSame: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual := NewRedactor(tc.ExtraPaths, nil).RedactBody(tc.Input)
+			expected := tc.Expected
+			if tc.ExpectedSame {
+				expected = tc.Input
+			}
+			if actual != expected {
+				t.Fatalf("expected %s, got %s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedactor_RedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer abc")
+	header.Set("X-Api-Key", "def")
+	header.Add("Accept", "application/json")
+	header.Add("Accept", "text/plain")
+
+	actual := NewRedactor(nil, []string{"x-api-key"}).RedactHeaders(header)
+	expected := map[string]string{
+		"Authorization": "REDACTED",
+		"X-Api-Key":     "REDACTED",
+		"Accept":        "application/json,text/plain",
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+}
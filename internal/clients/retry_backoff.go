@@ -0,0 +1,202 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/microsoft/terraform-provider-msgraph/internal/utils"
+)
+
+type retryOptionsKey struct{}
+
+// withRetryOptions returns a context which carries the retry options of a request. The retry options which are created by
+// NewRetryOptions are applied by the backoffRetryPolicy, so the retries of azcore are disabled for them.
+func withRetryOptions(ctx context.Context, options *policy.RetryOptions) context.Context {
+	if options == nil {
+		return ctx
+	}
+	if !hasBackoffDelay(options) {
+		return policy.WithRetryOptions(ctx, *options)
+	}
+	ctx = context.WithValue(ctx, retryOptionsKey{}, *options)
+	return policy.WithRetryOptions(ctx, policy.RetryOptions{MaxRetries: -1})
+}
+
+type retryStateKey struct{}
+
+// retryState tracks the attempts of a request and the delay before its next attempt. It allows the retry options to bound
+// their own attempts and to grow their own delays, even if they're combined with other retry options by CombineRetryOptions.
+type retryState struct {
+	attempts int32
+	// delay is the delay before the next attempt in nanoseconds, it's set by the ShouldRetry function of a retryBackoff.
+	delay int64
+	// maxAttempts is the maximum attempts of the retryBackoff which checked the response, 0 means unbounded.
+	maxAttempts int32
+}
+
+func (s *retryState) Attempts() int {
+	return int(atomic.LoadInt32(&s.attempts))
+}
+
+// backoffRetryPolicy retries the requests whose retry options are created by NewRetryOptions. Unlike the retry policy of
+// azcore, it waits the delay of the retryBackoff, and it honours a Retry-After header which exceeds the maximum delay.
+type backoffRetryPolicy struct{}
+
+func (backoffRetryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	options, ok := req.Raw().Context().Value(retryOptionsKey{}).(policy.RetryOptions)
+	if !ok || options.ShouldRetry == nil {
+		return req.Next()
+	}
+	maxRetries := int(options.MaxRetries)
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	state := &retryState{}
+	ctx := context.WithValue(req.Raw().Context(), retryStateKey{}, state)
+	for {
+		if err := req.RewindBody(); err != nil {
+			return nil, err
+		}
+		atomic.AddInt32(&state.attempts, 1)
+		resp, err := req.Clone(ctx).Next()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp, ctxErr
+		}
+		var nonRetriable interface{ NonRetriable() }
+		if errors.As(err, &nonRetriable) {
+			return resp, err
+		}
+		shouldRetryErr := err
+		if err != nil {
+			// the retry state is only reachable from the response, so it's attached to the error which has no response
+			shouldRetryErr = &retryAttemptError{err: err, state: state}
+		}
+		if !options.ShouldRetry(resp, shouldRetryErr) || state.Attempts() > maxRetries {
+			return resp, err
+		}
+
+		delay := time.Duration(atomic.SwapInt64(&state.delay, 0))
+		if resp != nil {
+			if d := parseRetryAfter(resp); d > 0 {
+				delay = d
+			}
+		}
+		runtime.Drain(resp)
+		log.Printf("[DEBUG] Retrying %s %s in %s, attempt %d", req.Raw().Method, req.Raw().URL.Path, delay, state.Attempts())
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// retryAttemptError attaches the retry state to an error which has no response.
+type retryAttemptError struct {
+	err   error
+	state *retryState
+}
+
+func (e *retryAttemptError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAttemptError) Unwrap() error {
+	return e.err
+}
+
+// retryStateOf returns the retry state of the request of the response or of the error, it's nil if the attempts aren't tracked.
+func retryStateOf(resp *http.Response, err error) *retryState {
+	if resp != nil && resp.Request != nil {
+		if state, ok := resp.Request.Context().Value(retryStateKey{}).(*retryState); ok {
+			return state
+		}
+	}
+	var attemptErr *retryAttemptError
+	if errors.As(err, &attemptErr) {
+		return attemptErr.state
+	}
+	return nil
+}
+
+// retryBackoff bounds the attempts of a request and grows the delay between them by a multiplier. If the multiplier is 0,
+// the delay grows exponentially with jitter, like the one of azcore.
+type retryBackoff struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+}
+
+// delay returns the delay after the attempt, the first attempt is 1.
+func (b retryBackoff) delay(attempt int) time.Duration {
+	var delay float64
+	if b.Multiplier > 0 {
+		delay = float64(b.InitialDelay) * math.Pow(b.Multiplier, float64(attempt-1))
+	} else {
+		// ((2 ^ attempt) - 1) * delay * random(0.8, 1.3)
+		delay = (math.Pow(2, float64(attempt)) - 1) * float64(b.InitialDelay) * (rand.Float64()/2 + 0.8)
+	}
+	if delay > float64(b.MaxDelay) {
+		return b.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// wrap returns a ShouldRetry function which stops retrying after the maximum attempts, and sets the delay before the next
+// attempt in the retry state, the delay of a Retry-After header takes precedence over it.
+func (b retryBackoff) wrap(shouldRetry func(*http.Response, error) bool) func(*http.Response, error) bool {
+	return func(resp *http.Response, err error) bool {
+		state := retryStateOf(resp, err)
+		attempt := 0
+		if state != nil {
+			attempt = state.Attempts()
+			if b.MaxAttempts > 0 {
+				atomic.StoreInt32(&state.maxAttempts, int32(b.MaxAttempts))
+			}
+		}
+		if b.MaxAttempts > 0 && attempt >= b.MaxAttempts {
+			return false
+		}
+		if !shouldRetry(resp, err) {
+			return false
+		}
+		if state != nil {
+			atomic.StoreInt64(&state.delay, int64(b.delay(max(attempt, 1))))
+		}
+		return true
+	}
+}
+
+// responseIsRetryable returns whether the JMESPath expression evaluated against the body of the error response is truthy.
+func responseIsRetryable(resp *http.Response, expression string) bool {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return false
+	}
+	payload, err := runtime.Payload(resp)
+	if err != nil || len(payload) == 0 {
+		return false
+	}
+	var body interface{}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return false
+	}
+	retryable, err := utils.EvaluateJMESCondition(body, expression)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to evaluate the retry expression %q: %v", expression, err)
+		return false
+	}
+	if retryable {
+		log.Printf("[DEBUG] Retrying request due to response matching expression %s", expression)
+	}
+	return retryable
+}
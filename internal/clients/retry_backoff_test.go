@@ -0,0 +1,222 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+func TestRetryBackoff_Delay(t *testing.T) {
+	b := retryBackoff{InitialDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2}
+	testcases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 5 * time.Second},
+	}
+
+	for _, tc := range testcases {
+		if got := b.delay(tc.attempt); got != tc.want {
+			t.Errorf("delay(%d): got %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestRetryBackoff_DelayWithJitter(t *testing.T) {
+	b := retryBackoff{InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+	testcases := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 800 * time.Millisecond, max: 1300 * time.Millisecond},
+		{attempt: 2, min: 2400 * time.Millisecond, max: 3900 * time.Millisecond},
+		{attempt: 3, min: 5 * time.Second, max: 5 * time.Second},
+	}
+
+	for _, tc := range testcases {
+		if got := b.delay(tc.attempt); got < tc.min || got > tc.max {
+			t.Errorf("delay(%d): got %v, want between %v and %v", tc.attempt, got, tc.min, tc.max)
+		}
+	}
+}
+
+// newRetryTestServer responds with the status code and body until the given number of requests have been served.
+func newRetryTestServer(failures int32, statusCode int, body string) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, &requests
+}
+
+func sendRetryTestRequest(t *testing.T, url string, options *policy.RetryOptions) *http.Response {
+	resp, err := doRetryTestRequest(url, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func doRetryTestRequest(url string, options *policy.RetryOptions) (*http.Response, error) {
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{
+		PerCall: []policy.Policy{backoffRetryPolicy{}},
+	}, nil)
+	req, err := runtime.NewRequest(withRetryOptions(context.Background(), options), http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+	return pl.Do(req)
+}
+
+func TestNewRetryOptions_ResponseIsRetryable(t *testing.T) {
+	server, requests := newRetryTestServer(2, http.StatusNotFound, `{"error":{"code":"Request_ResourceNotFound","message":"not found"}}`)
+	defer server.Close()
+
+	options := newRetryOptions(nil, nil, "error.code == 'Request_ResourceNotFound'")
+	options.RetryDelay = time.Millisecond
+	resp := sendRetryTestRequest(t, server.URL, options)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("requests: got %d, want 3", got)
+	}
+}
+
+func TestNewRetryOptions_StatusCodes(t *testing.T) {
+	server, requests := newRetryTestServer(1, http.StatusConflict, `{}`)
+	defer server.Close()
+
+	options := newRetryOptions(nil, []int{http.StatusConflict}, "")
+	options.RetryDelay = time.Millisecond
+	resp := sendRetryTestRequest(t, server.URL, options)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("requests: got %d, want 2", got)
+	}
+}
+
+func TestRetryBackoff_MaxAttempts(t *testing.T) {
+	server, requests := newRetryTestServer(10, http.StatusServiceUnavailable, `{}`)
+	defer server.Close()
+
+	b := retryBackoff{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, Multiplier: 2}
+	options := newRetryOptions(nil, nil, "")
+	options.ShouldRetry = b.wrap(options.ShouldRetry)
+	withBackoffDelay(options)
+	// the maximum attempts are applied even if they're combined with options which retry until the timeout
+	resp := sendRetryTestRequest(t, server.URL, CombineRetryOptions(options, &policy.RetryOptions{MaxRetries: 100}))
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status code: got %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("requests: got %d, want 3", got)
+	}
+}
+
+func TestRetryBackoff_Delay_DoesNotModifyResponse(t *testing.T) {
+	server, requests := newRetryTestServer(2, http.StatusServiceUnavailable, `{}`)
+	defer server.Close()
+
+	b := retryBackoff{InitialDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond, Multiplier: 1}
+	options := newRetryOptions(nil, nil, "")
+	var headers []http.Header
+	options.ShouldRetry = b.wrap(func(resp *http.Response, err error) bool {
+		headers = append(headers, resp.Header)
+		return resp.StatusCode == http.StatusServiceUnavailable
+	})
+	withBackoffDelay(options)
+	start := time.Now()
+	resp := sendRetryTestRequest(t, server.URL, options)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadInt32(requests); got != 3 {
+		t.Errorf("requests: got %d, want 3", got)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("elapsed: got %v, want at least 20ms", elapsed)
+	}
+	for _, header := range headers {
+		if v := header.Get("Retry-After-Ms"); v != "" {
+			t.Errorf("unexpected Retry-After-Ms header %q", v)
+		}
+	}
+}
+
+func TestRetryBackoff_RetryAfterExceedsMaxDelay(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After-Ms", "20")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	b := retryBackoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	options := newRetryOptions(nil, nil, "")
+	options.ShouldRetry = b.wrap(options.ShouldRetry)
+	withBackoffDelay(options)
+	resp := sendRetryTestRequest(t, server.URL, options)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests: got %d, want 2", got)
+	}
+}
+
+func TestRetryBackoff_MaxAttempts_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var errs int32
+	b := retryBackoff{MaxAttempts: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	options := &policy.RetryOptions{ShouldRetry: b.wrap(func(resp *http.Response, err error) bool {
+		atomic.AddInt32(&errs, 1)
+		return err != nil
+	})}
+	withBackoffDelay(options)
+	options.MaxRetries = 100
+
+	_, err := doRetryTestRequest(url, options)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	var attemptErr *retryAttemptError
+	if errors.As(err, &attemptErr) {
+		t.Errorf("the retry state should only be attached to the error passed to ShouldRetry")
+	}
+	// the second attempt isn't checked by the wrapped function
+	if got := atomic.LoadInt32(&errs); got != 1 {
+		t.Errorf("checked errors: got %d, want 1", got)
+	}
+}
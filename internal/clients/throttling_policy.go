@@ -0,0 +1,229 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	headerThrottleLimitPercentage = "X-Ms-Throttle-Limit-Percentage"
+	headerThrottleScope           = "X-Ms-Throttle-Scope"
+	headerThrottleInformation     = "X-Ms-Throttle-Information"
+
+	// defaultThrottlingBackoff is used when a throttled response doesn't contain a Retry-After header.
+	defaultThrottlingBackoff = 5 * time.Second
+
+	// nearLimitThreshold is the value of the x-ms-throttle-limit-percentage header from which the requests to the
+	// workload are slowed down before the service starts to reject them.
+	nearLimitThreshold = 0.95
+	nearLimitBackoff   = time.Second
+)
+
+// workloadFamilies maps the first segment of a Microsoft Graph URL to the workload which enforces the throttling
+// limits. The segments which are not listed here are considered as a workload of their own.
+var workloadFamilies = map[string]string{
+	"administrativeunits":    "directory",
+	"applications":           "directory",
+	"contacts":               "directory",
+	"devices":                "directory",
+	"directory":              "directory",
+	"directoryobjects":       "directory",
+	"directoryroles":         "directory",
+	"domains":                "directory",
+	"groups":                 "directory",
+	"oauth2permissiongrants": "directory",
+	"organization":           "directory",
+	"serviceprincipals":      "directory",
+	"users":                  "directory",
+	"identity":               "identity",
+	"identitygovernance":     "identity",
+	"identityprotection":     "identity",
+	"policies":               "identity",
+	"rolemanagement":         "identity",
+	"deviceappmanagement":    "intune",
+	"devicemanagement":       "intune",
+	"chats":                  "teams",
+	"teams":                  "teams",
+	"teamwork":               "teams",
+	"drives":                 "sharepoint",
+	"sites":                  "sharepoint",
+}
+
+// workloadFamily returns the workload of a Microsoft Graph URL path, e.g. `/v1.0/users/{id}` belongs to `directory`.
+func workloadFamily(urlPath string) string {
+	for _, segment := range strings.Split(urlPath, "/") {
+		if segment == "" || segment == "v1.0" || segment == "beta" {
+			continue
+		}
+		segment = strings.ToLower(segment)
+		if i := strings.IndexAny(segment, "('"); i > 0 {
+			segment = segment[:i]
+		}
+		if family, ok := workloadFamilies[segment]; ok {
+			return family
+		}
+		return segment
+	}
+	return ""
+}
+
+// throttlingScheduler is a per-retry policy which is shared by all requests sent by a Client. It caps the number of
+// concurrent requests, and when Microsoft Graph throttles a workload, it pauses all requests to that workload until
+// the backoff window requested by the service has passed.
+type throttlingScheduler struct {
+	// slots limits the number of concurrent requests, it's nil when the concurrency is unlimited.
+	slots chan struct{}
+
+	mu      sync.Mutex
+	backoff map[string]time.Time
+	summary throttlingSummary
+}
+
+type throttlingSummary struct {
+	Requests    int
+	Throttled   int
+	Unavailable int
+	NearLimit   int
+	WaitTime    time.Duration
+	ByWorkload  map[string]int
+}
+
+func newThrottlingScheduler(maxConcurrentRequests int) *throttlingScheduler {
+	s := &throttlingScheduler{
+		backoff: make(map[string]time.Time),
+		summary: throttlingSummary{
+			ByWorkload: make(map[string]int),
+		},
+	}
+	if maxConcurrentRequests > 0 {
+		s.slots = make(chan struct{}, maxConcurrentRequests)
+	}
+	return s
+}
+
+func (s *throttlingScheduler) Do(req *policy.Request) (*http.Response, error) {
+	ctx := req.Raw().Context()
+	workload := workloadFamily(req.Raw().URL.Path)
+	if err := s.wait(ctx, workload); err != nil {
+		return nil, err
+	}
+
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	resp, err := req.Next()
+	if s.slots != nil {
+		<-s.slots
+	}
+
+	s.observe(workload, resp)
+	return resp, err
+}
+
+// wait blocks until the backoff window of the workload has passed or the context is cancelled.
+func (s *throttlingScheduler) wait(ctx context.Context, workload string) error {
+	for {
+		s.mu.Lock()
+		delay := time.Until(s.backoff[workload])
+		s.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		log.Printf("[DEBUG] Workload %q is throttled, waiting %s before sending the request", workload, delay)
+		trace.SpanFromContext(ctx).AddEvent("throttled", trace.WithAttributes(
+			attribute.String("msgraph.workload", workload),
+			attribute.String("msgraph.throttling_delay", delay.String()),
+		))
+		start := time.Now()
+		select {
+		case <-ctx.Done():
+			s.addWaitTime(time.Since(start))
+			return ctx.Err()
+		case <-time.After(delay):
+			s.addWaitTime(time.Since(start))
+		}
+	}
+}
+
+// observe records the throttling signals of the response and extends the backoff window of the workload.
+func (s *throttlingScheduler) observe(workload string, resp *http.Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.summary.Requests++
+	if resp == nil {
+		return
+	}
+
+	var delay time.Duration
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		s.summary.Throttled++
+		s.summary.ByWorkload[workload]++
+		if delay = parseRetryAfter(resp); delay == 0 {
+			delay = defaultThrottlingBackoff
+		}
+		log.Printf("[DEBUG] Workload %q is throttled (scope: %q, information: %q), pausing requests for %s", workload, resp.Header.Get(headerThrottleScope), resp.Header.Get(headerThrottleInformation), delay)
+	case http.StatusServiceUnavailable:
+		s.summary.Unavailable++
+		if delay = parseRetryAfter(resp); delay > 0 {
+			log.Printf("[DEBUG] Workload %q is unavailable, pausing requests for %s", workload, delay)
+		}
+	default:
+		if v, err := strconv.ParseFloat(resp.Header.Get(headerThrottleLimitPercentage), 64); err == nil && v >= nearLimitThreshold {
+			s.summary.NearLimit++
+			delay = nearLimitBackoff
+			log.Printf("[DEBUG] Workload %q consumed %.0f%% of its throttling limit, slowing down requests", workload, v*100)
+		}
+	}
+
+	if delay > 0 {
+		if until := time.Now().Add(delay); until.After(s.backoff[workload]) {
+			s.backoff[workload] = until
+		}
+	}
+}
+
+func (s *throttlingScheduler) addWaitTime(d time.Duration) {
+	s.mu.Lock()
+	s.summary.WaitTime += d
+	s.mu.Unlock()
+}
+
+// Summary returns a copy of the throttling statistics.
+func (s *throttlingScheduler) Summary() throttlingSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.summary
+	out.ByWorkload = make(map[string]int, len(s.summary.ByWorkload))
+	for k, v := range s.summary.ByWorkload {
+		out.ByWorkload[k] = v
+	}
+	return out
+}
+
+func (s throttlingSummary) String() string {
+	workloads := make([]string, 0, len(s.ByWorkload))
+	for workload, count := range s.ByWorkload {
+		workloads = append(workloads, fmt.Sprintf("%s=%d", workload, count))
+	}
+	sort.Strings(workloads)
+	return fmt.Sprintf("%d requests, %d throttled (429), %d unavailable (503), %d near the throttling limit, total wait time %s, throttled by workload: [%s]",
+		s.Requests, s.Throttled, s.Unavailable, s.NearLimit, s.WaitTime.Round(time.Millisecond), strings.Join(workloads, ", "))
+}
@@ -0,0 +1,160 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+func TestWorkloadFamily(t *testing.T) {
+	testcases := []struct {
+		path string
+		want string
+	}{
+		{path: "/v1.0/users/1", want: "directory"},
+		{path: "/beta/servicePrincipals", want: "directory"},
+		{path: "/v1.0/teams('1')/operations('2')", want: "teams"},
+		{path: "/v1.0/deviceManagement/deviceConfigurations", want: "intune"},
+		{path: "/v1.0/identity/conditionalAccess/policies", want: "identity"},
+		{path: "/v1.0/print/printers", want: "print"},
+		{path: "/", want: ""},
+	}
+
+	for _, tc := range testcases {
+		if got := workloadFamily(tc.path); got != tc.want {
+			t.Errorf("workloadFamily(%q): got %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func newTestPipeline(scheduler *throttlingScheduler) runtime.Pipeline {
+	return runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Retry:            policy.RetryOptions{MaxRetries: -1},
+		PerRetryPolicies: []policy.Policy{scheduler},
+	})
+}
+
+func sendTestRequest(t *testing.T, pl runtime.Pipeline, url string) *http.Response {
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := pl.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func TestThrottlingScheduler_PausesThrottledWorkload(t *testing.T) {
+	var throttled int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1.0/users" && atomic.CompareAndSwapInt32(&throttled, 0, 1) {
+			w.Header().Set("Retry-After-Ms", "200")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheduler := newThrottlingScheduler(0)
+	pl := newTestPipeline(scheduler)
+
+	if resp := sendTestRequest(t, pl, server.URL+"/v1.0/users"); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the first request to be throttled, got %d", resp.StatusCode)
+	}
+
+	// requests to another workload are not paused
+	start := time.Now()
+	sendTestRequest(t, pl, server.URL+"/v1.0/teams")
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("request to another workload was paused for %s", elapsed)
+	}
+
+	// requests to the same workload family wait for the backoff window
+	sendTestRequest(t, pl, server.URL+"/v1.0/groups")
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("request to the throttled workload was not paused, elapsed %s", elapsed)
+	}
+
+	summary := scheduler.Summary()
+	if summary.Requests != 3 || summary.Throttled != 1 || summary.ByWorkload["directory"] != 1 || summary.WaitTime == 0 {
+		t.Fatalf("unexpected summary: %s", summary)
+	}
+}
+
+func TestClient_LogThrottlingSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After-Ms", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	client := &Client{scheduler: newThrottlingScheduler(0)}
+	pl := newTestPipeline(client.scheduler)
+	sendTestRequest(t, pl, server.URL+"/v1.0/users")
+	sendTestRequest(t, pl, server.URL+"/v1.0/users")
+	if strings.Contains(buf.String(), "[INFO]") {
+		t.Fatalf("expected the throttled requests to be only logged at DEBUG, got %s", buf.String())
+	}
+
+	client.LogThrottlingSummary()
+	if count := strings.Count(buf.String(), "[INFO] Microsoft Graph throttling summary: 2 requests, 2 throttled (429)"); count != 1 {
+		t.Fatalf("expected the throttling summary to be logged once, got %s", buf.String())
+	}
+}
+
+func TestThrottlingScheduler_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pl := newTestPipeline(newThrottlingScheduler(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL+"/v1.0/users")
+			if err == nil {
+				_, err = pl.Do(req)
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+}
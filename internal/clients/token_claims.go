@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	IdentityTypeUser             = "user"
+	IdentityTypeServicePrincipal = "service_principal"
+	IdentityTypeManagedIdentity  = "managed_identity"
+)
+
+// TokenClaims are the claims of a Microsoft Graph access token which describe the authenticated principal.
+type TokenClaims struct {
+	TenantId          string
+	ClientId          string
+	ObjectId          string
+	IdentityType      string
+	UserPrincipalName string
+	Roles             []string
+	Scopes            []string
+}
+
+type accessTokenClaims struct {
+	TenantId          string   `json:"tid"`
+	AppId             string   `json:"appid"`
+	AuthorizedParty   string   `json:"azp"`
+	ObjectId          string   `json:"oid"`
+	IdentityType      string   `json:"idtyp"`
+	ManagedIdentityId string   `json:"xms_mirid"`
+	UserPrincipalName string   `json:"upn"`
+	UniqueName        string   `json:"unique_name"`
+	Roles             []string `json:"roles"`
+	Scope             string   `json:"scp"`
+}
+
+// ParseTokenClaims decodes the claims of the access token. The signature isn't verified, the token is only used to describe
+// the principal which the provider is authenticated as.
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("the access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding the payload of the access token: %w", err)
+	}
+	var claims accessTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("parsing the claims of the access token: %w", err)
+	}
+
+	out := &TokenClaims{
+		TenantId:          claims.TenantId,
+		ClientId:          claims.AppId,
+		ObjectId:          claims.ObjectId,
+		UserPrincipalName: claims.UserPrincipalName,
+		Roles:             claims.Roles,
+		Scopes:            strings.Fields(claims.Scope),
+	}
+	// v2.0 tokens have the client ID in `azp` instead of `appid`
+	if out.ClientId == "" {
+		out.ClientId = claims.AuthorizedParty
+	}
+	if out.UserPrincipalName == "" && claims.Scope != "" {
+		out.UserPrincipalName = claims.UniqueName
+	}
+	if out.Roles == nil {
+		out.Roles = []string{}
+	}
+
+	// delegated tokens have scopes, app-only tokens have `idtyp` set to `app` and managed identities have their resource ID in `xms_mirid`
+	switch {
+	case claims.IdentityType == "user" || (claims.IdentityType == "" && claims.Scope != ""):
+		out.IdentityType = IdentityTypeUser
+	case claims.ManagedIdentityId != "":
+		out.IdentityType = IdentityTypeManagedIdentity
+	default:
+		out.IdentityType = IdentityTypeServicePrincipal
+	}
+	return out, nil
+}
+
+// TokenClaims obtains an access token for Microsoft Graph from the credential of the client, and returns its claims.
+func (client *Client) TokenClaims(ctx context.Context) (*TokenClaims, error) {
+	token, err := client.Option.Cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{graphScope(client.Option.CloudCfg)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ParseTokenClaims(token.Token)
+}
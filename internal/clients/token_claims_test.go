@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+func testToken(payload string) string {
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	cases := []struct {
+		name    string
+		token   string
+		want    *TokenClaims
+		wantErr bool
+	}{
+		{
+			name:  "user",
+			token: testToken(`{"tid":"tenant","appid":"04b07795-8ddb-461a-bbee-02f9e1bf7b46","oid":"user","upn":"user@contoso.com","scp":"User.Read Application.ReadWrite.All"}`),
+			want: &TokenClaims{
+				TenantId:          "tenant",
+				ClientId:          "04b07795-8ddb-461a-bbee-02f9e1bf7b46",
+				ObjectId:          "user",
+				IdentityType:      IdentityTypeUser,
+				UserPrincipalName: "user@contoso.com",
+				Roles:             []string{},
+				Scopes:            []string{"User.Read", "Application.ReadWrite.All"},
+			},
+		},
+		{
+			name:  "service principal",
+			token: testToken(`{"tid":"tenant","azp":"app","oid":"sp","idtyp":"app","roles":["Application.ReadWrite.All"]}`),
+			want: &TokenClaims{
+				TenantId:     "tenant",
+				ClientId:     "app",
+				ObjectId:     "sp",
+				IdentityType: IdentityTypeServicePrincipal,
+				Roles:        []string{"Application.ReadWrite.All"},
+				Scopes:       []string{},
+			},
+		},
+		{
+			name:  "managed identity",
+			token: testToken(`{"tid":"tenant","appid":"app","oid":"mi","idtyp":"app","xms_mirid":"/subscriptions/sub/resourcegroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/mi"}`),
+			want: &TokenClaims{
+				TenantId:     "tenant",
+				ClientId:     "app",
+				ObjectId:     "mi",
+				IdentityType: IdentityTypeManagedIdentity,
+				Roles:        []string{},
+				Scopes:       []string{},
+			},
+		},
+		{
+			name:    "not a JWT",
+			token:   "opaque",
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			token:   "header.!!!.signature",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseTokenClaims(tc.token)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestChainedCredential_Name(t *testing.T) {
+	cred, err := NewChainedCredential([]NamedCredential{
+		{Name: "azure_cli", Credential: fakeCredential{}},
+		{Name: "azure_powershell", Credential: fakeCredential{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name := cred.Name(); name != "" {
+		t.Fatalf("expected no name before a token is issued, got %q", name)
+	}
+	token, err := cred.GetToken(context.Background(), policy.TokenRequestOptions{Scopes: []string{"https://graph.microsoft.com/.default"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.ExpiresOn.Before(time.Now()) {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+	if name := cred.Name(); name != "azure_cli" {
+		t.Fatalf("expected azure_cli, got %q", name)
+	}
+}
@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/tracing"
+	"github.com/microsoft/terraform-provider-msgraph/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "terraform-provider-msgraph"
+
+// TracingOptions configures where the spans of the provider are exported to, tracing is disabled when both are empty.
+type TracingOptions struct {
+	// Endpoint is the URL of an OTLP/HTTP collector, e.g. `http://localhost:4318`.
+	Endpoint string
+	// File is the path of a file which the spans are appended to as JSON.
+	File string
+}
+
+func (o TracingOptions) Enabled() bool {
+	return o.Endpoint != "" || o.File != ""
+}
+
+// NewTracingProvider returns a provider which exports the spans via OpenTelemetry. The spans are exported synchronously
+// when they end, because the provider process can be stopped by Terraform at any time without a chance to flush them.
+func NewTracingProvider(ctx context.Context, o TracingOptions) (tracing.Provider, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", tracerName),
+			attribute.String("service.version", version.ProviderVersion),
+		)),
+	}
+	if o.Endpoint != "" {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(o.Endpoint))
+		if err != nil {
+			return tracing.Provider{}, fmt.Errorf("creating the OTLP exporter of %q: %w", o.Endpoint, err)
+		}
+		options = append(options, sdktrace.WithSyncer(exporter))
+	}
+	if o.File != "" {
+		// #nosec G304
+		f, err := os.OpenFile(o.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return tracing.Provider{}, fmt.Errorf("opening the trace file %q: %w", o.File, err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			return tracing.Provider{}, fmt.Errorf("creating the file exporter of %q: %w", o.File, err)
+		}
+		options = append(options, sdktrace.WithSyncer(exporter))
+	}
+	return otelTracingProvider(sdktrace.NewTracerProvider(options...)), nil
+}
+
+// StartSpan starts a span of a provider operation, e.g. the creation of a resource. The requests sent with the returned
+// context are traced as its children, with a span per attempt. It's a no-op when tracing is disabled.
+func (client *MSGraphClient) StartSpan(ctx context.Context, name string, url string, apiVersion string) (context.Context, func(error)) {
+	return runtime.StartSpan(ctx, name, client.tracer, &runtime.StartSpanOptions{
+		Attributes: []tracing.Attribute{
+			{Key: "msgraph.url", Value: url},
+			{Key: "msgraph.api_version", Value: apiVersion},
+		},
+	})
+}
+
+// otelTracingProvider adapts the OpenTelemetry tracer provider to the tracing abstraction of azcore.
+func otelTracingProvider(tp trace.TracerProvider) tracing.Provider {
+	return tracing.NewProvider(func(name, version string) tracing.Tracer {
+		tracer := tp.Tracer(name, trace.WithInstrumentationVersion(version))
+		return tracing.NewTracer(func(ctx context.Context, spanName string, options *tracing.SpanOptions) (context.Context, tracing.Span) {
+			var startOptions []trace.SpanStartOption
+			if options != nil {
+				// the span kinds of azcore have the same values as the ones of OpenTelemetry
+				startOptions = append(startOptions, trace.WithSpanKind(trace.SpanKind(options.Kind)), trace.WithAttributes(otelAttributes(options.Attributes)...))
+			}
+			ctx, span := tracer.Start(ctx, spanName, startOptions...)
+			return ctx, otelSpan(span)
+		}, &tracing.TracerOptions{
+			SpanFromContext: func(ctx context.Context) tracing.Span {
+				return otelSpan(trace.SpanFromContext(ctx))
+			},
+		})
+	}, nil)
+}
+
+func otelSpan(span trace.Span) tracing.Span {
+	return tracing.NewSpan(tracing.SpanImpl{
+		End: func() {
+			span.End()
+		},
+		SetAttributes: func(attrs ...tracing.Attribute) {
+			span.SetAttributes(otelAttributes(attrs)...)
+		},
+		AddEvent: func(name string, attrs ...tracing.Attribute) {
+			span.AddEvent(name, trace.WithAttributes(otelAttributes(attrs)...))
+		},
+		SetStatus: func(status tracing.SpanStatus, description string) {
+			switch status {
+			case tracing.SpanStatusError:
+				span.SetStatus(codes.Error, description)
+			case tracing.SpanStatusOK:
+				span.SetStatus(codes.Ok, description)
+			default:
+				span.SetStatus(codes.Unset, description)
+			}
+		},
+	})
+}
+
+func otelAttributes(attrs []tracing.Attribute) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			out = append(out, attribute.String(attr.Key, v))
+		case int:
+			out = append(out, attribute.Int(attr.Key, v))
+		case int64:
+			out = append(out, attribute.Int64(attr.Key, v))
+		case float64:
+			out = append(out, attribute.Float64(attr.Key, v))
+		case bool:
+			out = append(out, attribute.Bool(attr.Key, v))
+		default:
+			out = append(out, attribute.String(attr.Key, fmt.Sprintf("%v", v)))
+		}
+	}
+	return out
+}
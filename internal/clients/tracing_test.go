@@ -0,0 +1,90 @@
+package clients
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+func TestStartSpan_File(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	file := filepath.Join(t.TempDir(), "traces.json")
+	provider, err := NewTracingProvider(context.Background(), TracingOptions{File: file})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client, err := NewMSGraphClient(fakeCredential{}, &policy.ClientOptions{
+		Cloud: cloud.Configuration{
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				ServiceNameMSGraph: {Endpoint: server.URL, Audience: "https://graph.microsoft.com"},
+			},
+		},
+		Retry:           policy.RetryOptions{MaxRetries: -1},
+		TracingProvider: provider,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, end := client.StartSpan(context.Background(), "msgraph_resource.Read", "applications/1", "v1.0")
+	if _, err := client.Read(ctx, "applications/1", "v1.0", DefaultRequestOptions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	end(nil)
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	type span struct {
+		Name        string
+		SpanContext struct{ TraceID, SpanID string }
+		Parent      struct{ SpanID string }
+	}
+	spans := make(map[string]span)
+	decoder := json.NewDecoder(bufio.NewReader(f))
+	for decoder.More() {
+		var s span
+		if err := decoder.Decode(&s); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		spans[s.Name] = s
+	}
+
+	operation, ok := spans["msgraph_resource.Read"]
+	if !ok {
+		t.Fatalf("expected the span of the operation, got %v", spans)
+	}
+	request, ok := spans["HTTP GET"]
+	if !ok {
+		t.Fatalf("expected the span of the request, got %v", spans)
+	}
+	if request.SpanContext.TraceID != operation.SpanContext.TraceID || request.Parent.SpanID != operation.SpanContext.SpanID {
+		t.Fatalf("expected the request to be traced as a child of the operation, got %+v and %+v", request, operation)
+	}
+}
+
+func TestStartSpan_Disabled(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ctx := context.Background()
+	spanCtx, end := client.StartSpan(ctx, "msgraph_resource.Read", "applications/1", "v1.0")
+	end(nil)
+	if spanCtx != ctx {
+		t.Fatal("expected no span to be started when tracing is disabled")
+	}
+}
@@ -0,0 +1,209 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+const (
+	// UploadChunkSizeUnit is the unit of the chunk sizes of an upload session, all chunks except the last one must be a multiple of it.
+	UploadChunkSizeUnit = 320 * 1024
+	// DefaultUploadChunkSize is the chunk size recommended by Microsoft Graph, 10 MiB.
+	DefaultUploadChunkSize = 32 * UploadChunkSizeUnit
+)
+
+// maxUploadResumes is the maximum number of times an upload is resumed after a chunk failed with a transient error.
+var maxUploadResumes = 5
+
+type uploadSession struct {
+	UploadUrl          string   `json:"uploadUrl"`
+	NextExpectedRanges []string `json:"nextExpectedRanges"`
+}
+
+// Upload creates an upload session via the `createUploadSession` action at the url, and uploads the content to it in chunks of
+// the chunk size. When a chunk fails with a transient error, the status of the session is queried and the upload is resumed
+// from the next expected range. It returns the body of the last response, e.g. the uploaded drive item.
+func (client *MSGraphClient) Upload(ctx context.Context, url string, apiVersion string, body interface{}, content io.ReaderAt, size int64, chunkSize int64, options RequestOptions) (interface{}, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("the content is empty, an upload session requires at least one byte")
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultUploadChunkSize
+	}
+
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	responseBody, err := client.Create(ctx, url, apiVersion, body, options)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseUploadSession(responseBody)
+	if err != nil {
+		return nil, err
+	}
+	if session.UploadUrl == "" {
+		return nil, fmt.Errorf("the response of %s doesn't contain the `uploadUrl` of the upload session", url)
+	}
+
+	// apply per-request retry options via context
+	if options.RetryOptions != nil {
+		ctx = withRetryOptions(ctx, options.RetryOptions)
+	}
+
+	offset := int64(0)
+	resumes := 0
+	for {
+		end := offset + chunkSize
+		if end > size {
+			end = size
+		}
+		responseBody, next, err := client.uploadChunk(ctx, session.UploadUrl, content, offset, end-1, size)
+		if err != nil {
+			if !isTransientUploadError(err) || resumes >= maxUploadResumes {
+				client.cancelUploadSession(ctx, session.UploadUrl)
+				return nil, err
+			}
+			resumes++
+			log.Printf("[DEBUG] Uploading the range %d-%d failed, resuming the upload session (%d/%d): %v", offset, end-1, resumes, maxUploadResumes, err)
+			if next, err = client.uploadSessionOffset(ctx, session.UploadUrl); err != nil {
+				client.cancelUploadSession(ctx, session.UploadUrl)
+				return nil, err
+			}
+		}
+		if next < 0 {
+			return responseBody, nil
+		}
+		offset = next
+	}
+}
+
+// uploadChunk uploads the range of the content, it returns the offset of the next expected range, or -1 and the response
+// body if the upload is completed.
+func (client *MSGraphClient) uploadChunk(ctx context.Context, uploadUrl string, content io.ReaderAt, start int64, end int64, size int64) (interface{}, int64, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodPut, uploadUrl)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := req.SetBody(streaming.NopCloser(io.NewSectionReader(content, start, end-start+1)), defaultRawContentType); err != nil {
+		return nil, 0, err
+	}
+	req.Raw().Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	resp, err := client.unauthenticatedPl.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		responseBody, err := unmarshalResponseBody(resp)
+		return responseBody, -1, err
+	case http.StatusAccepted:
+		var session uploadSession
+		if err := runtime.UnmarshalAsJSON(resp, &session); err != nil {
+			return nil, 0, err
+		}
+		if next, ok := nextExpectedOffset(session.NextExpectedRanges); ok {
+			return nil, next, nil
+		}
+		return nil, end + 1, nil
+	default:
+		return nil, 0, runtime.NewResponseError(resp)
+	}
+}
+
+// uploadSessionOffset returns the offset of the next expected range of the upload session.
+func (client *MSGraphClient) uploadSessionOffset(ctx context.Context, uploadUrl string) (int64, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, uploadUrl)
+	if err != nil {
+		return 0, err
+	}
+	req.Raw().Header.Set("Accept", "application/json")
+	resp, err := client.unauthenticatedPl.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return 0, runtime.NewResponseError(resp)
+	}
+	var session uploadSession
+	if err := runtime.UnmarshalAsJSON(resp, &session); err != nil {
+		return 0, err
+	}
+	next, ok := nextExpectedOffset(session.NextExpectedRanges)
+	if !ok {
+		return 0, fmt.Errorf("the upload session doesn't expect any range, but the upload isn't completed")
+	}
+	return next, nil
+}
+
+// cancelUploadSession deletes the upload session, so the uploaded chunks are discarded. It's best effort, errors are only logged.
+func (client *MSGraphClient) cancelUploadSession(ctx context.Context, uploadUrl string) {
+	req, err := runtime.NewRequest(context.WithoutCancel(ctx), http.MethodDelete, uploadUrl)
+	if err != nil {
+		return
+	}
+	if resp, err := client.unauthenticatedPl.Do(req); err != nil {
+		log.Printf("[DEBUG] Failed to cancel the upload session: %v", err)
+	} else if !runtime.HasStatusCode(resp, http.StatusNoContent, http.StatusOK) {
+		log.Printf("[DEBUG] Failed to cancel the upload session: %v", runtime.NewResponseError(resp))
+	}
+}
+
+func parseUploadSession(responseBody interface{}) (uploadSession, error) {
+	session := uploadSession{}
+	responseMap, ok := responseBody.(map[string]interface{})
+	if !ok {
+		return session, fmt.Errorf("the response of the upload session is not a JSON object: %v", responseBody)
+	}
+	session.UploadUrl, _ = responseMap["uploadUrl"].(string)
+	if ranges, ok := responseMap["nextExpectedRanges"].([]interface{}); ok {
+		for _, r := range ranges {
+			if s, ok := r.(string); ok {
+				session.NextExpectedRanges = append(session.NextExpectedRanges, s)
+			}
+		}
+	}
+	return session, nil
+}
+
+// nextExpectedOffset returns the start of the first range, e.g. `26` of `["26-", "40-50"]`.
+func nextExpectedOffset(ranges []string) (int64, bool) {
+	if len(ranges) == 0 {
+		return 0, false
+	}
+	start, _, _ := strings.Cut(ranges[0], "-")
+	offset, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return offset, true
+}
+
+// isTransientUploadError returns whether the upload can be resumed after the error: a network error, a retryable status
+// code, or a range which doesn't match the next expected range of the session.
+func isTransientUploadError(err error) bool {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if responseErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return true
+	}
+	for _, code := range DefaultRetryableStatusCodes {
+		if responseErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
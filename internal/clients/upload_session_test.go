@@ -0,0 +1,169 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newUploadServer returns a client of a server which serves an upload session, the chunk handler is called for each uploaded chunk
+// and returns the status code to respond with, or 0 to accept the chunk.
+func newUploadServer(t *testing.T, failChunk func(start int64) int) (*MSGraphClient, *bytes.Buffer, *[]string) {
+	var (
+		mu       sync.Mutex
+		received bytes.Buffer
+		requests []string
+		baseUrl  string
+	)
+	const size = 10
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, r.Header.Get("Content-Range")))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/createUploadSession"):
+			_, _ = fmt.Fprintf(w, `{"uploadUrl":"%s/upload","nextExpectedRanges":["0-"]}`, baseUrl)
+		case r.URL.Path == "/upload" && r.Header.Get("Authorization") != "":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/upload" && r.Method == http.MethodGet:
+			_, _ = fmt.Fprintf(w, `{"nextExpectedRanges":["%d-"]}`, received.Len())
+		case r.URL.Path == "/upload" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/upload" && r.Method == http.MethodPut:
+			var start, end, total int64
+			_, _ = fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total)
+			if statusCode := failChunk(start); statusCode != 0 {
+				w.WriteHeader(statusCode)
+				_, _ = w.Write([]byte(`{"error":{"code":"failed","message":"failed"}}`))
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			if start != int64(received.Len()) || int64(len(data)) != end-start+1 || total != size {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			received.Write(data)
+			if int64(received.Len()) == total {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = fmt.Fprintf(w, `{"id":"item","size":%d}`, total)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = fmt.Fprintf(w, `{"nextExpectedRanges":["%d-"]}`, received.Len())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	baseUrl = client.GraphBaseUrl()
+	return client, &received, &requests
+}
+
+func TestUpload(t *testing.T) {
+	testcases := []struct {
+		Name             string
+		ChunkSize        int64
+		FailChunk        func(start int64) int
+		ExpectedRequests string
+		ExpectError      bool
+	}{
+		{
+			Name:      "single chunk",
+			ChunkSize: 0,
+			ExpectedRequests: "[POST /v1.0/drives/1/root:/a.txt:/createUploadSession  " +
+				"PUT /upload bytes 0-9/10]",
+		},
+		{
+			Name:      "multiple chunks",
+			ChunkSize: 4,
+			ExpectedRequests: "[POST /v1.0/drives/1/root:/a.txt:/createUploadSession  " +
+				"PUT /upload bytes 0-3/10 PUT /upload bytes 4-7/10 PUT /upload bytes 8-9/10]",
+		},
+		{
+			Name:      "resume after a transient failure",
+			ChunkSize: 4,
+			FailChunk: func() func(start int64) int {
+				failed := false
+				return func(start int64) int {
+					if start == 4 && !failed {
+						failed = true
+						return http.StatusServiceUnavailable
+					}
+					return 0
+				}
+			}(),
+			ExpectedRequests: "[POST /v1.0/drives/1/root:/a.txt:/createUploadSession  " +
+				"PUT /upload bytes 0-3/10 PUT /upload bytes 4-7/10 GET /upload  PUT /upload bytes 4-7/10 PUT /upload bytes 8-9/10]",
+		},
+		{
+			Name:      "cancel after a permanent failure",
+			ChunkSize: 4,
+			FailChunk: func(start int64) int {
+				if start == 4 {
+					return http.StatusBadRequest
+				}
+				return 0
+			},
+			ExpectedRequests: "[POST /v1.0/drives/1/root:/a.txt:/createUploadSession  " +
+				"PUT /upload bytes 0-3/10 PUT /upload bytes 4-7/10 DELETE /upload ]",
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			failChunk := tc.FailChunk
+			if failChunk == nil {
+				failChunk = func(int64) int { return 0 }
+			}
+			client, received, requests := newUploadServer(t, failChunk)
+
+			content := []byte("0123456789")
+			responseBody, err := client.Upload(context.Background(), "/drives/1/root:/a.txt:/createUploadSession", "v1.0", nil, bytes.NewReader(content), int64(len(content)), tc.ChunkSize, RequestOptions{})
+			if actual := fmt.Sprint(*requests); actual != tc.ExpectedRequests {
+				t.Fatalf("expected requests %s, got %s", tc.ExpectedRequests, actual)
+			}
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if received.String() != string(content) {
+				t.Fatalf("expected the content to be uploaded, got %q", received.String())
+			}
+			if fmt.Sprint(responseBody) != "map[id:item size:10]" {
+				t.Fatalf("unexpected response body: %v", responseBody)
+			}
+		})
+	}
+}
+
+func TestNextExpectedOffset(t *testing.T) {
+	testcases := []struct {
+		Ranges   []string
+		Expected int64
+		Ok       bool
+	}{
+		{Ranges: nil, Ok: false},
+		{Ranges: []string{"26-"}, Expected: 26, Ok: true},
+		{Ranges: []string{"12345-55232", "77829-99375"}, Expected: 12345, Ok: true},
+		{Ranges: []string{"invalid"}, Ok: false},
+	}
+
+	for _, tc := range testcases {
+		actual, ok := nextExpectedOffset(tc.Ranges)
+		if actual != tc.Expected || ok != tc.Ok {
+			t.Errorf("expected %d %v for %v, got %d %v", tc.Expected, tc.Ok, tc.Ranges, actual, ok)
+		}
+	}
+}
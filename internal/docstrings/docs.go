@@ -3,7 +3,7 @@ package docstrings
 import "fmt"
 
 func ApiVersion() string {
-	return "The API version of the data source. The allowed values are `v1.0` and `beta`. Defaults to `v1.0`."
+	return "The API version of the data source. The allowed values are `v1.0` and `beta`. Defaults to the `api_version` of the provider's `defaults` block, or `v1.0`."
 }
 
 func Url(kind string) string {
@@ -42,6 +42,26 @@ func Body() string {
 	return "A dynamic attribute that contains the request body."
 }
 
+func SensitiveBody() string {
+	return "A dynamic attribute that contains the secret properties of the request body, e.g. `passwordProfile.password`. It's deep merged into `body` when the resource is created or updated. " +
+		"It's write-only, so it's never stored in the plan or state, only its hash is kept in the private state to detect its changes, and the resource is updated with it when it changes, e.g. to rotate a password. " +
+		"Its properties must not be set in `body`. An imported resource is updated with it on the first apply. It requires Terraform 1.11 or later."
+}
+
+func BodyBase64() string {
+	return "The base64 encoded request body, which is sent as it is instead of JSON, e.g. the content of a photo or a logo. It conflicts with `body`. Use the `filebase64` function to read a local file."
+}
+
+func ContentType() string {
+	return "The content type of `body_base64`, e.g. `image/jpeg`. Defaults to `application/octet-stream`."
+}
+
+func OperationUrl(operation string) string {
+	return fmt.Sprintf("The URL of the %[1]s request, when the resource isn't addressed by %[2]surl/{id}%[2]s, e.g. a password added by %[2]saddPassword%[2]s is read via its application. "+
+		"It supports the %[2]s{id}%[2]s placeholder and %[2]s{response.<path>}%[2]s placeholders, where the path is a JMESPath query against the response of the create request, e.g. %[2]s{response.keyId}%[2]s. "+
+		"Defaults to the URL of the resource instance.", operation, "`")
+}
+
 func Output() string {
 	return fmt.Sprintf(`
 The output HCL object containing the properties specified in %[1]sresponse_export_values%[1]s. Here are some examples to use the values.
@@ -83,5 +103,43 @@ func ResourceID() string {
 }
 
 func IgnoreMissingProperty() string {
-	return "Whether ignore not returned properties like credentials in `body` to suppress plan-diff. Defaults to `true`. It's recommend to enable this option when some sensitive properties are not returned in response body, instead of setting them in `lifecycle.ignore_changes` because it will make the sensitive fields unable to update."
+	return "Whether ignore not returned properties like credentials in `body` to suppress plan-diff. Defaults to the `ignore_missing_property` of the provider's `defaults` block, or `true`. It's recommend to enable this option when some sensitive properties are not returned in response body, instead of setting them in `lifecycle.ignore_changes` because it will make the sensitive fields unable to update."
+}
+
+func PollLongRunningOperation() string {
+	return "Whether to wait for the long-running operation when the API responds with `202 Accepted` and an `Operation-Location` or `Location` header. When enabled, the operation is polled, honouring the `Retry-After` header, until it reaches a terminal status or the timeout is reached. If the operation fails, its error is returned. When it creates the resource, the resource is read from the location which the succeeded operation refers to, i.e. its `resourceLocation`, its `targetResourceLocation` or a `Location` header. Defaults to `false`."
+}
+
+func DeleteBehavior() string {
+	return "What happens to the resource when it's destroyed. The allowed values are `delete`, `purge` and `detach`. Defaults to `delete`.\n" +
+		"  - `delete`: The resource is deleted. Deleted directory objects, e.g. applications, groups and users, are kept in `directory/deletedItems` for 30 days, and their unique properties can't be reused meanwhile.\n" +
+		"  - `purge`: The resource is deleted, then it's permanently deleted from `directory/deletedItems/{id}`. It only applies to the directory objects created in `administrativeUnits`, `applications`, `groups`, `servicePrincipals` and `users`.\n" +
+		"  - `detach`: The resource is only removed from the state, no request is sent to Microsoft Graph, e.g. for an auto-provisioned service principal or a break-glass account which must never be deleted."
+}
+
+func ETagMode() string {
+	return "Whether to use the `@odata.etag` of the resource for optimistic concurrency. The allowed values are `disabled`, `strict` and `refresh`. Defaults to `disabled`.\n" +
+		"  - `disabled`: No precondition is sent, concurrent changes made by others are overwritten.\n" +
+		"  - `strict`: The `@odata.etag` captured by the last read is sent in the `If-Match` header of the update and delete requests. If the resource was modified by others in the meantime, the request fails with `412 Precondition Failed`.\n" +
+		"  - `refresh`: Same as `strict`, but when the request fails with `412 Precondition Failed`, the resource is re-read, the changes are re-computed against its latest state, and the request is retried with the latest `@odata.etag`."
+}
+
+func MaxItems() string {
+	return "The maximum number of items returned from a collection. When it's reached, no more pages are fetched and `truncated` is set to `true`. Defaults to unlimited."
+}
+
+func MaxPages() string {
+	return "The maximum number of pages fetched from a collection by following the `@odata.nextLink`. When it's reached while more pages are available, `truncated` is set to `true`. Defaults to unlimited."
+}
+
+func ActionMaxPages() string {
+	return "The maximum number of pages fetched from a collection by following the `@odata.nextLink`. When it's reached while more pages are available, `truncated` is set to `true`. If neither `max_items` nor `max_pages` is specified, only the first page of the response is returned."
+}
+
+func PageSize() string {
+	return "The number of items requested per page via the `$top` query parameter. It's ignored if the `$top` query parameter is specified in `query_parameters`. Defaults to the page size of the API."
+}
+
+func Truncated() string {
+	return "Whether the collection was truncated by `max_items` or `max_pages`, so more items are available."
 }
@@ -0,0 +1,40 @@
+package myvalidator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type int64IsMultipleOf struct {
+	factor int64
+}
+
+func (v int64IsMultipleOf) Description(ctx context.Context) string {
+	return fmt.Sprintf("validates that the value is a multiple of %d", v.factor)
+}
+
+func (v int64IsMultipleOf) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64IsMultipleOf) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	value := req.ConfigValue
+
+	if value.IsUnknown() || value.IsNull() {
+		return
+	}
+
+	if value.ValueInt64()%v.factor != 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid value",
+			fmt.Sprintf("The value must be a multiple of %d, got %d.", v.factor, value.ValueInt64()),
+		)
+	}
+}
+
+func Int64IsMultipleOf(factor int64) validator.Int64 {
+	return int64IsMultipleOf{factor: factor}
+}
@@ -0,0 +1,45 @@
+package myvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestInt64IsMultipleOf_ValidateInt64(t *testing.T) {
+	v := int64IsMultipleOf{factor: 327680}
+
+	cases := []struct {
+		name      string
+		value     basetypes.Int64Value
+		wantError bool
+	}{
+		{"multiple", basetypes.NewInt64Value(10485760), false},
+		{"factor", basetypes.NewInt64Value(327680), false},
+		{"not a multiple", basetypes.NewInt64Value(1000000), true},
+		{"null", basetypes.NewInt64Null(), false},
+		{"unknown", basetypes.NewInt64Unknown(), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.Int64Request{
+				ConfigValue: tc.value,
+				Path:        path.Empty(),
+			}
+			resp := &validator.Int64Response{
+				Diagnostics: diag.Diagnostics{},
+			}
+
+			v.ValidateInt64(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tc.wantError {
+				t.Errorf("expected error %v, got: %v", tc.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package myvalidator
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type stringIsBase64 struct{}
+
+func (v stringIsBase64) Description(ctx context.Context) string {
+	return "validates that the string is standard base64 encoded"
+}
+
+func (v stringIsBase64) MarkdownDescription(ctx context.Context) string {
+	return "validates that the string is standard base64 encoded"
+}
+
+func (stringIsBase64) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(str.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid base64 string",
+			err.Error(),
+		)
+	}
+}
+
+func StringIsBase64() validator.String {
+	return stringIsBase64{}
+}
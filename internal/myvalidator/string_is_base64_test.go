@@ -0,0 +1,46 @@
+package myvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestStringIsBase64_ValidateString(t *testing.T) {
+	v := stringIsBase64{}
+
+	cases := []struct {
+		name      string
+		value     basetypes.StringValue
+		wantError bool
+	}{
+		{"valid", basetypes.NewStringValue("aGVsbG8="), false},
+		{"empty", basetypes.NewStringValue(""), false},
+		{"null", basetypes.NewStringNull(), false},
+		{"unknown", basetypes.NewStringUnknown(), false},
+		{"invalid characters", basetypes.NewStringValue("hello!"), true},
+		{"missing padding", basetypes.NewStringValue("aGVsbG8"), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: tc.value,
+				Path:        path.Empty(),
+			}
+			resp := &validator.StringResponse{
+				Diagnostics: diag.Diagnostics{},
+			}
+
+			v.ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tc.wantError {
+				t.Errorf("expected error %v, got: %v", tc.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
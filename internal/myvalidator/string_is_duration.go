@@ -0,0 +1,47 @@
+package myvalidator
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type stringIsDuration struct{}
+
+func (v stringIsDuration) Description(ctx context.Context) string {
+	return "validates that the string can be parsed as a positive Go duration, e.g. `30s`"
+}
+
+func (v stringIsDuration) MarkdownDescription(ctx context.Context) string {
+	return "validates that the string can be parsed as a positive Go duration, e.g. `30s`"
+}
+
+func (stringIsDuration) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	str := req.ConfigValue
+
+	if str.IsUnknown() || str.IsNull() {
+		return
+	}
+
+	d, err := time.ParseDuration(str.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			err.Error(),
+		)
+		return
+	}
+	if d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			"the duration must be positive",
+		)
+	}
+}
+
+func StringIsDuration() validator.String {
+	return stringIsDuration{}
+}
@@ -0,0 +1,43 @@
+package myvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestStringIsDuration_ValidateString(t *testing.T) {
+	testcases := []struct {
+		Name      string
+		Input     string
+		ExpectErr bool
+	}{
+		{Name: "seconds", Input: "30s"},
+		{Name: "combined", Input: "1m30s"},
+		{Name: "no unit", Input: "30", ExpectErr: true},
+		{Name: "zero", Input: "0s", ExpectErr: true},
+		{Name: "negative", Input: "-1s", ExpectErr: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := validator.StringRequest{
+				ConfigValue: basetypes.NewStringValue(tc.Input),
+				Path:        path.Empty(),
+			}
+			resp := &validator.StringResponse{
+				Diagnostics: diag.Diagnostics{},
+			}
+
+			stringIsDuration{}.ValidateString(context.Background(), req, resp)
+
+			if resp.Diagnostics.HasError() != tc.ExpectErr {
+				t.Errorf("Expected error %v, but got: %v", tc.ExpectErr, resp.Diagnostics)
+			}
+		})
+	}
+}
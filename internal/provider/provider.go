@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -26,31 +32,120 @@ import (
 
 var _ provider.Provider = &MSGraphProvider{}
 
-type MSGraphProvider struct{}
+type MSGraphProvider struct {
+	// Transport and Credential override the HTTP transport and the credential of the client.
+	// They're used by the acceptance tests to record and replay the traffic.
+	Transport  policy.Transporter
+	Credential azcore.TokenCredential
+
+	// builtClients are the clients built by Configure, their throttling summary is logged when the provider stops.
+	mu           sync.Mutex
+	builtClients []*clients.Client
+}
 
 type MSGraphProviderModel struct {
-	ClientID                     types.String `tfsdk:"client_id"`
-	ClientIDFilePath             types.String `tfsdk:"client_id_file_path"`
-	TenantID                     types.String `tfsdk:"tenant_id"`
-	ClientCertificatePath        types.String `tfsdk:"client_certificate_path"`
-	ClientCertificate            types.String `tfsdk:"client_certificate"`
-	ClientCertificatePassword    types.String `tfsdk:"client_certificate_password"`
-	ClientSecret                 types.String `tfsdk:"client_secret"`
-	ClientSecretFilePath         types.String `tfsdk:"client_secret_file_path"`
-	OIDCRequestToken             types.String `tfsdk:"oidc_request_token"`
-	OIDCRequestURL               types.String `tfsdk:"oidc_request_url"`
-	OIDCToken                    types.String `tfsdk:"oidc_token"`
-	OIDCTokenFilePath            types.String `tfsdk:"oidc_token_file_path"`
-	OIDCAzureServiceConnectionID types.String `tfsdk:"oidc_azure_service_connection_id"`
-	UseOIDC                      types.Bool   `tfsdk:"use_oidc"`
-	UseCLI                       types.Bool   `tfsdk:"use_cli"`
-	UsePowerShell                types.Bool   `tfsdk:"use_powershell"`
-	UseMSI                       types.Bool   `tfsdk:"use_msi"`
-	UseAKSWorkloadIdentity       types.Bool   `tfsdk:"use_aks_workload_identity"`
-	PartnerID                    types.String `tfsdk:"partner_id"`
-	CustomCorrelationRequestID   types.String `tfsdk:"custom_correlation_request_id"`
-	DisableCorrelationRequestID  types.Bool   `tfsdk:"disable_correlation_request_id"`
-	DisableTerraformPartnerID    types.Bool   `tfsdk:"disable_terraform_partner_id"`
+	ClientID                     types.String   `tfsdk:"client_id"`
+	ClientIDFilePath             types.String   `tfsdk:"client_id_file_path"`
+	TenantID                     types.String   `tfsdk:"tenant_id"`
+	ClientCertificatePath        types.String   `tfsdk:"client_certificate_path"`
+	ClientCertificate            types.String   `tfsdk:"client_certificate"`
+	ClientCertificatePassword    types.String   `tfsdk:"client_certificate_password"`
+	ClientSecret                 types.String   `tfsdk:"client_secret"`
+	ClientSecretFilePath         types.String   `tfsdk:"client_secret_file_path"`
+	OIDCRequestToken             types.String   `tfsdk:"oidc_request_token"`
+	OIDCRequestURL               types.String   `tfsdk:"oidc_request_url"`
+	OIDCToken                    types.String   `tfsdk:"oidc_token"`
+	OIDCTokenFilePath            types.String   `tfsdk:"oidc_token_file_path"`
+	OIDCAzureServiceConnectionID types.String   `tfsdk:"oidc_azure_service_connection_id"`
+	UseOIDC                      types.Bool     `tfsdk:"use_oidc"`
+	UseCLI                       types.Bool     `tfsdk:"use_cli"`
+	UsePowerShell                types.Bool     `tfsdk:"use_powershell"`
+	UseMSI                       types.Bool     `tfsdk:"use_msi"`
+	UseAKSWorkloadIdentity       types.Bool     `tfsdk:"use_aks_workload_identity"`
+	PartnerID                    types.String   `tfsdk:"partner_id"`
+	CustomCorrelationRequestID   types.String   `tfsdk:"custom_correlation_request_id"`
+	DisableCorrelationRequestID  types.Bool     `tfsdk:"disable_correlation_request_id"`
+	DisableTerraformPartnerID    types.Bool     `tfsdk:"disable_terraform_partner_id"`
+	Environment                  types.String   `tfsdk:"environment"`
+	GraphEndpoint                types.String   `tfsdk:"graph_endpoint"`
+	AuthorityHost                types.String   `tfsdk:"authority_host"`
+	MaxConcurrentRequests        types.Int64    `tfsdk:"max_concurrent_requests"`
+	RedactedJsonPaths            types.List     `tfsdk:"redacted_json_paths"`
+	RedactedHeaders              types.List     `tfsdk:"redacted_headers"`
+	TracingEndpoint              types.String   `tfsdk:"tracing_endpoint"`
+	TracingFile                  types.String   `tfsdk:"tracing_file"`
+	Defaults                     *DefaultsModel `tfsdk:"defaults"`
+}
+
+type DefaultsModel struct {
+	ApiVersion            types.String           `tfsdk:"api_version"`
+	IgnoreMissingProperty types.Bool             `tfsdk:"ignore_missing_property"`
+	Headers               types.Map              `tfsdk:"headers"`
+	Retry                 *DefaultsRetryModel    `tfsdk:"retry"`
+	Timeouts              *DefaultsTimeoutsModel `tfsdk:"timeouts"`
+}
+
+type DefaultsRetryModel struct {
+	ErrorMessageRegex types.List `tfsdk:"error_message_regex"`
+}
+
+type DefaultsTimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+// ClientDefaults converts the `defaults` block to the defaults of the client.
+func (model *DefaultsModel) ClientDefaults() (clients.Defaults, error) {
+	out := clients.Defaults{}
+	if model == nil {
+		return out, nil
+	}
+	out.ApiVersion = model.ApiVersion.ValueString()
+	if !model.IgnoreMissingProperty.IsNull() && !model.IgnoreMissingProperty.IsUnknown() {
+		out.IgnoreMissingProperty = model.IgnoreMissingProperty.ValueBoolPointer()
+	}
+	if !model.Headers.IsNull() && !model.Headers.IsUnknown() {
+		out.Headers = make(map[string]string)
+		for key, value := range model.Headers.Elements() {
+			if v, ok := value.(types.String); ok {
+				out.Headers[key] = v.ValueString()
+			}
+		}
+	}
+	if model.Retry != nil {
+		for _, value := range model.Retry.ErrorMessageRegex.Elements() {
+			v, ok := value.(types.String)
+			if !ok {
+				continue
+			}
+			re, err := regexp.Compile(v.ValueString())
+			if err != nil {
+				return out, fmt.Errorf("compiling the retry pattern %q: %w", v.ValueString(), err)
+			}
+			out.RetryErrorMessageRegex = append(out.RetryErrorMessageRegex, *re)
+		}
+	}
+	if model.Timeouts != nil {
+		out.Timeouts = make(map[string]time.Duration)
+		for operation, value := range map[string]types.String{
+			"create": model.Timeouts.Create,
+			"read":   model.Timeouts.Read,
+			"update": model.Timeouts.Update,
+			"delete": model.Timeouts.Delete,
+		} {
+			if value.ValueString() == "" {
+				continue
+			}
+			d, err := time.ParseDuration(value.ValueString())
+			if err != nil {
+				return out, fmt.Errorf("parsing the %s timeout %q: %w", operation, value.ValueString(), err)
+			}
+			out.Timeouts[operation] = d
+		}
+	}
+	return out, nil
 }
 
 func New() func() provider.Provider {
@@ -250,6 +345,122 @@ func (p *MSGraphProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:            true,
 				MarkdownDescription: "Disable sending the Terraform Partner ID if a custom `partner_id` isn't specified, which allows Microsoft to better understand the usage of Terraform. The Partner ID does not give HashiCorp any direct access to usage information. This can also be sourced from the `ARM_DISABLE_TERRAFORM_PARTNER_ID` environment variable. Defaults to `false`.",
 			},
+
+			// Cloud specific fields
+			"environment": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOfCaseInsensitive(clients.Environments...),
+				},
+				MarkdownDescription: "The national cloud to use. Possible values are `public`, `usgovernment` (US Government L4), `dod` (US Government L5) and `china` (operated by 21Vianet). This can also be sourced from the `ARM_ENVIRONMENT` Environment Variable. Defaults to `public`. Note that the Azure CLI and Azure PowerShell credentials use the cloud which is configured in the respective tool.",
+			},
+
+			"graph_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The base URL of the Microsoft Graph API, for example `https://graph.microsoft.us`. It overrides the endpoint of the `environment`, which is useful for a proxy or a local stand-in of Microsoft Graph. Access tokens are still requested for the Microsoft Graph API of the `environment`. This can also be sourced from the `ARM_GRAPH_ENDPOINT` Environment Variable.",
+			},
+
+			"authority_host": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The Microsoft Entra authority host used to obtain access tokens, for example `https://login.microsoftonline.us/`. It overrides the authority host of the `environment`. This can also be sourced from the `ARM_AUTHORITY_HOST` Environment Variable.",
+			},
+
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				MarkdownDescription: "The maximum number of concurrent requests sent to Microsoft Graph by the provider. Requests to a workload which is throttled by Microsoft Graph are paused until the `Retry-After` period has passed, regardless of this setting. This can also be sourced from the `ARM_MAX_CONCURRENT_REQUESTS` Environment Variable. Defaults to unlimited.",
+			},
+
+			"redacted_json_paths": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A list of JSON paths whose values are redacted from the request and response bodies in the debug logs, in addition to the built-in sensitive properties, e.g. `secretText`, `passwordProfile.password` and `keyCredentials.key`. A path is a dot separated list of property names, which matches a property if it's the suffix of the property's path, array indexes are not part of the path. For example, `passwordCredentials.secretText` matches the `secretText` of all items in the `passwordCredentials` array.",
+			},
+
+			"redacted_headers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A list of header names whose values are redacted from the requests and responses in the debug logs, in addition to the built-in sensitive headers, e.g. `Authorization`.",
+			},
+
+			"tracing_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The URL of an OpenTelemetry collector which receives the traces of the provider via OTLP/HTTP, for example `http://localhost:4318`. A span is created for each operation of a resource or data source, with a child span for each attempt of a request to Microsoft Graph. This can also be sourced from the `ARM_TRACING_ENDPOINT` Environment Variable. Tracing is disabled by default.",
+			},
+
+			"tracing_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The path of a file which the spans of the provider are appended to as JSON, the spans are the same as the ones sent to `tracing_endpoint`. This can also be sourced from the `ARM_TRACING_FILE` Environment Variable. Tracing is disabled by default.",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"defaults": schema.SingleNestedBlock{
+				MarkdownDescription: "The defaults of all resources and data sources of the provider. A value configured in a resource or data source takes precedence over its default.",
+				Attributes: map[string]schema.Attribute{
+					"api_version": schema.StringAttribute{
+						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("v1.0", "beta"),
+						},
+						MarkdownDescription: "The default API version of the resources and data sources. Allowed values are `v1.0` and `beta`. Defaults to `v1.0`.",
+					},
+
+					"ignore_missing_property": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "The default of `ignore_missing_property` of the `msgraph_resource` and `msgraph_update_resource` resources. Defaults to `true`.",
+					},
+
+					"headers": schema.MapAttribute{
+						ElementType:         types.StringType,
+						Optional:            true,
+						MarkdownDescription: "A mapping of headers which are sent with all requests to Microsoft Graph, for example `ConsistencyLevel = \"eventual\"`. A header set by a resource or data source, e.g. in `headers`, takes precedence.",
+					},
+
+					"retry": schema.SingleNestedAttribute{
+						Optional: true,
+						Attributes: map[string]schema.Attribute{
+							"error_message_regex": schema.ListAttribute{
+								ElementType: types.StringType,
+								Required:    true,
+								Validators: []validator.List{
+									listvalidator.ValueStringsAre(myvalidator.StringIsValidRegex()),
+									listvalidator.UniqueValues(),
+									listvalidator.SizeAtLeast(1),
+								},
+								MarkdownDescription: "A list of regular expressions to match against error messages. If any of the regular expressions match, the request will be retried.",
+							},
+						},
+						MarkdownDescription: "The default `retry` of the resources and data sources. It's used by the resources and data sources which don't configure `retry`, the patterns aren't merged.",
+					},
+				},
+
+				Blocks: map[string]schema.Block{
+					"timeouts": schema.SingleNestedBlock{
+						Attributes: map[string]schema.Attribute{
+							"create": schema.StringAttribute{
+								Optional:            true,
+								MarkdownDescription: "The default timeout of the create operations, a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration), e.g. `30m`.",
+							},
+							"read": schema.StringAttribute{
+								Optional:            true,
+								MarkdownDescription: "The default timeout of the read operations, a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration), e.g. `5m`.",
+							},
+							"update": schema.StringAttribute{
+								Optional:            true,
+								MarkdownDescription: "The default timeout of the update operations, a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration), e.g. `30m`.",
+							},
+							"delete": schema.StringAttribute{
+								Optional:            true,
+								MarkdownDescription: "The default timeout of the delete operations, a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration), e.g. `30m`.",
+							},
+						},
+						MarkdownDescription: "The default timeouts of the resources and data sources. A timeout configured in the `timeouts` block of a resource or data source takes precedence.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -418,23 +629,101 @@ func (p *MSGraphProvider) Configure(ctx context.Context, req provider.ConfigureR
 		}
 	}
 
-	option := azidentity.DefaultAzureCredentialOptions{
-		TenantID: model.TenantID.ValueString(),
+	if model.Environment.IsNull() {
+		if v := os.Getenv("ARM_ENVIRONMENT"); v != "" {
+			model.Environment = types.StringValue(v)
+		} else {
+			model.Environment = types.StringValue(clients.EnvironmentPublic)
+		}
+	}
+
+	if model.GraphEndpoint.IsNull() {
+		if v := os.Getenv("ARM_GRAPH_ENDPOINT"); v != "" {
+			model.GraphEndpoint = types.StringValue(v)
+		}
+	}
+
+	if model.AuthorityHost.IsNull() {
+		if v := os.Getenv("ARM_AUTHORITY_HOST"); v != "" {
+			model.AuthorityHost = types.StringValue(v)
+		}
+	}
+
+	if model.MaxConcurrentRequests.IsNull() {
+		if v := os.Getenv("ARM_MAX_CONCURRENT_REQUESTS"); v != "" {
+			maxConcurrentRequests, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || maxConcurrentRequests < 1 {
+				resp.Diagnostics.AddError("Invalid `ARM_MAX_CONCURRENT_REQUESTS` value", fmt.Sprintf("expected a positive integer, got %q", v))
+				return
+			}
+			model.MaxConcurrentRequests = types.Int64Value(maxConcurrentRequests)
+		}
+	}
+
+	if model.TracingEndpoint.IsNull() {
+		if v := os.Getenv("ARM_TRACING_ENDPOINT"); v != "" {
+			model.TracingEndpoint = types.StringValue(v)
+		}
 	}
 
-	cred, err := BuildChainedTokenCredential(model, option)
+	if model.TracingFile.IsNull() {
+		if v := os.Getenv("ARM_TRACING_FILE"); v != "" {
+			model.TracingFile = types.StringValue(v)
+		}
+	}
+
+	var redactedPaths, redactedHeaders []string
+	resp.Diagnostics.Append(model.RedactedJsonPaths.ElementsAs(ctx, &redactedPaths, true)...)
+	resp.Diagnostics.Append(model.RedactedHeaders.ElementsAs(ctx, &redactedHeaders, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defaults, err := model.Defaults.ClientDefaults()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid `defaults` block", err.Error())
+		return
+	}
+
+	cloudCfg, err := clients.NewCloudConfiguration(model.Environment.ValueString(), model.GraphEndpoint.ValueString(), model.AuthorityHost.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to obtain a credential.", err.Error())
+		resp.Diagnostics.AddError("Invalid cloud configuration", err.Error())
 		return
 	}
 
+	option := azidentity.DefaultAzureCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud: cloudCfg,
+		},
+		TenantID: model.TenantID.ValueString(),
+	}
+
+	var cred azcore.TokenCredential = p.Credential
+	if cred == nil {
+		chainedCred, err := BuildChainedTokenCredential(model, option)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to obtain a credential.", err.Error())
+			return
+		}
+		cred = chainedCred
+	}
+
 	copt := &clients.Option{
 		Cred:                        cred,
 		ApplicationUserAgent:        buildUserAgent(req.TerraformVersion, model.PartnerID.ValueString(), model.DisableTerraformPartnerID.ValueBool()),
 		DisableCorrelationRequestID: model.DisableCorrelationRequestID.ValueBool(),
 		CustomCorrelationRequestID:  model.CustomCorrelationRequestID.ValueString(),
-		CloudCfg:                    cloud.Configuration{},
+		CloudCfg:                    cloudCfg,
 		TenantId:                    model.TenantID.ValueString(),
+		MaxConcurrentRequests:       int(model.MaxConcurrentRequests.ValueInt64()),
+		RedactedPaths:               redactedPaths,
+		RedactedHeaders:             redactedHeaders,
+		Transport:                   p.Transport,
+		Tracing: clients.TracingOptions{
+			Endpoint: model.TracingEndpoint.ValueString(),
+			File:     model.TracingFile.ValueString(),
+		},
+		Defaults: defaults,
 	}
 	client := &clients.Client{}
 	if err = client.Build(ctx, copt); err != nil {
@@ -442,16 +731,31 @@ func (p *MSGraphProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	p.mu.Lock()
+	p.builtClients = append(p.builtClients, client)
+	p.mu.Unlock()
+
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// Close logs the throttling summary of the clients built by the provider, it's called once when the provider stops.
+func (p *MSGraphProvider) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, client := range p.builtClients {
+		client.LogThrottlingSummary()
+	}
+	p.builtClients = nil
+}
+
 func (p *MSGraphProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		services.NewMSGraphResource,
 		services.NewMSGraphResourceAction,
 		services.NewMSGraphUpdateResource,
 		services.NewMSGraphResourceCollection,
+		services.NewMSGraphUpload,
 	}
 }
 
@@ -459,6 +763,8 @@ func (p *MSGraphProvider) DataSources(ctx context.Context) []func() datasource.D
 	return []func() datasource.DataSource{
 		services.NewMSGraphDataSource,
 		services.NewMSGraphResourceActionDataSource,
+		services.NewMSGraphResourceDeltaDataSource,
+		services.NewMSGraphClientConfigDataSource,
 	}
 }
 
@@ -491,34 +797,35 @@ func buildUserAgent(terraformVersion string, partnerID string, disableTerraformP
 	return userAgent
 }
 
-func BuildChainedTokenCredential(model MSGraphProviderModel, options azidentity.DefaultAzureCredentialOptions) (*azidentity.ChainedTokenCredential, error) {
+// BuildChainedTokenCredential returns a credential which tries the enabled authentication methods in order, and records which one succeeded.
+func BuildChainedTokenCredential(model MSGraphProviderModel, options azidentity.DefaultAzureCredentialOptions) (*clients.ChainedCredential, error) {
 	log.Printf("[DEBUG] building chained token credential")
-	var creds []azcore.TokenCredential
+	var creds []clients.NamedCredential
 
 	if model.UseOIDC.ValueBool() || model.UseAKSWorkloadIdentity.ValueBool() {
 		log.Printf("[DEBUG] oidc credential or AKS Workload Identity enabled")
 		if cred, err := buildOidcCredential(model, options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, clients.NamedCredential{Name: "oidc", Credential: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize oidc credential: %v", err)
 		}
 
 		log.Printf("[DEBUG] azure pipelines credential enabled")
 		if cred, err := buildAzurePipelinesCredential(model, options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, clients.NamedCredential{Name: "azure_pipelines", Credential: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize azure pipelines credential: %v", err)
 		}
 	}
 
 	if cred, err := buildClientSecretCredential(model, options); err == nil {
-		creds = append(creds, cred)
+		creds = append(creds, clients.NamedCredential{Name: "client_secret", Credential: cred})
 	} else {
 		log.Printf("[DEBUG] failed to initialize client secret credential: %v", err)
 	}
 
 	if cred, err := buildClientCertificateCredential(model, options); err == nil {
-		creds = append(creds, cred)
+		creds = append(creds, clients.NamedCredential{Name: "client_certificate", Credential: cred})
 	} else {
 		log.Printf("[DEBUG] failed to initialize client certificate credential: %v", err)
 	}
@@ -526,7 +833,7 @@ func BuildChainedTokenCredential(model MSGraphProviderModel, options azidentity.
 	if model.UseMSI.ValueBool() {
 		log.Printf("[DEBUG] msi credential enabled")
 		if cred, err := buildManagedIdentityCredential(model, options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, clients.NamedCredential{Name: "managed_identity", Credential: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize msi credential: %v", err)
 		}
@@ -535,7 +842,7 @@ func BuildChainedTokenCredential(model MSGraphProviderModel, options azidentity.
 	if model.UseCLI.ValueBool() {
 		log.Printf("[DEBUG] cli credential enabled")
 		if cred, err := buildAzureCLICredential(options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, clients.NamedCredential{Name: "azure_cli", Credential: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize cli credential: %v", err)
 		}
@@ -544,7 +851,7 @@ func BuildChainedTokenCredential(model MSGraphProviderModel, options azidentity.
 	if model.UsePowerShell.ValueBool() {
 		log.Printf("[DEBUG] powershell credential enabled")
 		if cred, err := buildAzurePowerShellCredential(options); err == nil {
-			creds = append(creds, cred)
+			creds = append(creds, clients.NamedCredential{Name: "azure_powershell", Credential: cred})
 		} else {
 			log.Printf("[DEBUG] failed to initialize powershell credential: %v", err)
 		}
@@ -554,7 +861,7 @@ func BuildChainedTokenCredential(model MSGraphProviderModel, options azidentity.
 		return nil, fmt.Errorf("no credentials were successfully initialized")
 	}
 
-	return azidentity.NewChainedTokenCredential(creds, nil)
+	return clients.NewChainedCredential(creds)
 }
 
 func buildClientSecretCredential(model MSGraphProviderModel, options azidentity.DefaultAzureCredentialOptions) (azcore.TokenCredential, error) {
@@ -3,6 +3,8 @@ package retry
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -15,7 +17,7 @@ func Schema(ctx context.Context) schema.Attribute {
 		Attributes: map[string]schema.Attribute{
 			"error_message_regex": schema.ListAttribute{
 				ElementType:         types.StringType,
-				Required:            true,
+				Optional:            true,
 				Description:         "A list of regular expressions to match against error messages. If any of the regular expressions match, the request will be retried.",
 				MarkdownDescription: "A list of regular expressions to match against error messages. If any of the regular expressions match, the request will be retried.",
 				Validators: []validator.List{
@@ -24,6 +26,57 @@ func Schema(ctx context.Context) schema.Attribute {
 					listvalidator.SizeAtLeast(1),
 				},
 			},
+			"status_codes": schema.ListAttribute{
+				ElementType:         types.Int64Type,
+				Optional:            true,
+				Description:         "A list of HTTP status codes which are retried in addition to the default ones, which are 408, 429, 500, 502, 503 and 504.",
+				MarkdownDescription: "A list of HTTP status codes which are retried in addition to the default ones, which are `408`, `429`, `500`, `502`, `503` and `504`.",
+				Validators: []validator.List{
+					listvalidator.ValueInt64sAre(int64validator.Between(400, 599)),
+					listvalidator.UniqueValues(),
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"max_attempts": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "The maximum number of attempts of a request, including the first one. By default, the request is retried until the timeout of the operation is reached.",
+				MarkdownDescription: "The maximum number of attempts of a request, including the first one. By default, the request is retried until the timeout of the operation is reached.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"initial_delay": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The delay before the first retry, e.g. 5s. Defaults to 800ms.",
+				MarkdownDescription: "The delay before the first retry, e.g. `5s`. Defaults to `800ms`.",
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+			},
+			"max_delay": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The maximum delay between the retries, e.g. 2m. The delay of a Retry-After header is honoured even if it exceeds it. Defaults to 60s.",
+				MarkdownDescription: "The maximum delay between the retries, e.g. `2m`. The delay of a `Retry-After` header is honoured even if it exceeds it. Defaults to `60s`.",
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+			},
+			"multiplier": schema.Float64Attribute{
+				Optional:            true,
+				Description:         "The factor which the delay is multiplied by after each retry, the delay doesn't grow if it's 1. By default, the delay grows exponentially with jitter. The delay of a Retry-After header takes precedence.",
+				MarkdownDescription: "The factor which the delay is multiplied by after each retry, the delay doesn't grow if it's `1`. By default, the delay grows exponentially with jitter. The delay of a `Retry-After` header takes precedence.",
+				Validators: []validator.Float64{
+					float64validator.AtLeast(1),
+				},
+			},
+			"response_is_retryable": schema.StringAttribute{
+				Optional:            true,
+				Description:         "A JMESPath expression which is evaluated against the body of an error response, the request is retried if the result is truthy, e.g. error.code == 'Request_ResourceNotFound'.",
+				MarkdownDescription: "A [JMESPath](https://jmespath.org/) expression which is evaluated against the body of an error response, the request is retried if the result is truthy, e.g. `error.code == 'Request_ResourceNotFound'`.",
+				Validators: []validator.String{
+					myvalidator.StringIsValidJMESPath(),
+				},
+			},
 		},
 		CustomType: Type{
 			ObjectType: types.ObjectType{
@@ -52,12 +52,126 @@ func (t Type) ValueFromObject(ctx context.Context, in basetypes.ObjectValue) (ba
 			fmt.Sprintf(`error_message_regex expected to be basetypes.ListValue, was: %T`, errorMessageRegexAttribute))
 	}
 
+	statusCodesAttribute, ok := attributes["status_codes"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`status_codes is missing from object`)
+
+		return nil, diags
+	}
+
+	statusCodesVal, ok := statusCodesAttribute.(basetypes.ListValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`status_codes expected to be basetypes.ListValue, was: %T`, statusCodesAttribute))
+	}
+
+	maxAttemptsAttribute, ok := attributes["max_attempts"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`max_attempts is missing from object`)
+
+		return nil, diags
+	}
+
+	maxAttemptsVal, ok := maxAttemptsAttribute.(basetypes.Int64Value)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`max_attempts expected to be basetypes.Int64Value, was: %T`, maxAttemptsAttribute))
+	}
+
+	initialDelayAttribute, ok := attributes["initial_delay"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`initial_delay is missing from object`)
+
+		return nil, diags
+	}
+
+	initialDelayVal, ok := initialDelayAttribute.(basetypes.StringValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`initial_delay expected to be basetypes.StringValue, was: %T`, initialDelayAttribute))
+	}
+
+	maxDelayAttribute, ok := attributes["max_delay"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`max_delay is missing from object`)
+
+		return nil, diags
+	}
+
+	maxDelayVal, ok := maxDelayAttribute.(basetypes.StringValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`max_delay expected to be basetypes.StringValue, was: %T`, maxDelayAttribute))
+	}
+
+	multiplierAttribute, ok := attributes["multiplier"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`multiplier is missing from object`)
+
+		return nil, diags
+	}
+
+	multiplierVal, ok := multiplierAttribute.(basetypes.Float64Value)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`multiplier expected to be basetypes.Float64Value, was: %T`, multiplierAttribute))
+	}
+
+	responseIsRetryableAttribute, ok := attributes["response_is_retryable"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`response_is_retryable is missing from object`)
+
+		return nil, diags
+	}
+
+	responseIsRetryableVal, ok := responseIsRetryableAttribute.(basetypes.StringValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`response_is_retryable expected to be basetypes.StringValue, was: %T`, responseIsRetryableAttribute))
+	}
+
 	if diags.HasError() {
 		return nil, diags
 	}
 
 	return Value{
-		ErrorMessageRegex: errorMessageRegexVal,
-		state:             attr.ValueStateKnown,
+		ErrorMessageRegex:   errorMessageRegexVal,
+		StatusCodes:         statusCodesVal,
+		MaxAttempts:         maxAttemptsVal,
+		InitialDelay:        initialDelayVal,
+		MaxDelay:            maxDelayVal,
+		Multiplier:          multiplierVal,
+		ResponseIsRetryable: responseIsRetryableVal,
+		state:               attr.ValueStateKnown,
 	}, diags
 }
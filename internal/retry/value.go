@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -96,13 +97,127 @@ func NewValue(attributeTypes map[string]attr.Type, attributes map[string]attr.Va
 			fmt.Sprintf(`error_message_regex expected to be basetypes.ListValue, was: %T`, errorMessageRegexAttribute))
 	}
 
+	statusCodesAttribute, ok := attributes["status_codes"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`status_codes is missing from object`)
+
+		return NewValueUnknown(), diags
+	}
+
+	statusCodesVal, ok := statusCodesAttribute.(basetypes.ListValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`status_codes expected to be basetypes.ListValue, was: %T`, statusCodesAttribute))
+	}
+
+	maxAttemptsAttribute, ok := attributes["max_attempts"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`max_attempts is missing from object`)
+
+		return NewValueUnknown(), diags
+	}
+
+	maxAttemptsVal, ok := maxAttemptsAttribute.(basetypes.Int64Value)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`max_attempts expected to be basetypes.Int64Value, was: %T`, maxAttemptsAttribute))
+	}
+
+	initialDelayAttribute, ok := attributes["initial_delay"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`initial_delay is missing from object`)
+
+		return NewValueUnknown(), diags
+	}
+
+	initialDelayVal, ok := initialDelayAttribute.(basetypes.StringValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`initial_delay expected to be basetypes.StringValue, was: %T`, initialDelayAttribute))
+	}
+
+	maxDelayAttribute, ok := attributes["max_delay"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`max_delay is missing from object`)
+
+		return NewValueUnknown(), diags
+	}
+
+	maxDelayVal, ok := maxDelayAttribute.(basetypes.StringValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`max_delay expected to be basetypes.StringValue, was: %T`, maxDelayAttribute))
+	}
+
+	multiplierAttribute, ok := attributes["multiplier"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`multiplier is missing from object`)
+
+		return NewValueUnknown(), diags
+	}
+
+	multiplierVal, ok := multiplierAttribute.(basetypes.Float64Value)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`multiplier expected to be basetypes.Float64Value, was: %T`, multiplierAttribute))
+	}
+
+	responseIsRetryableAttribute, ok := attributes["response_is_retryable"]
+
+	if !ok {
+		diags.AddError(
+			"Attribute Missing",
+			`response_is_retryable is missing from object`)
+
+		return NewValueUnknown(), diags
+	}
+
+	responseIsRetryableVal, ok := responseIsRetryableAttribute.(basetypes.StringValue)
+
+	if !ok {
+		diags.AddError(
+			"Attribute Wrong Type",
+			fmt.Sprintf(`response_is_retryable expected to be basetypes.StringValue, was: %T`, responseIsRetryableAttribute))
+	}
+
 	if diags.HasError() {
 		return NewValueUnknown(), diags
 	}
 
 	return Value{
-		ErrorMessageRegex: errorMessageRegexVal,
-		state:             attr.ValueStateKnown,
+		ErrorMessageRegex:   errorMessageRegexVal,
+		StatusCodes:         statusCodesVal,
+		MaxAttempts:         maxAttemptsVal,
+		InitialDelay:        initialDelayVal,
+		MaxDelay:            maxDelayVal,
+		Multiplier:          multiplierVal,
+		ResponseIsRetryable: responseIsRetryableVal,
+		state:               attr.ValueStateKnown,
 	}, diags
 }
 
@@ -172,12 +287,18 @@ func (t Type) ValueType(ctx context.Context) attr.Value {
 var _ basetypes.ObjectValuable = Value{}
 
 type Value struct {
-	ErrorMessageRegex basetypes.ListValue `tfsdk:"error_message_regex"`
-	state             attr.ValueState
+	ErrorMessageRegex   basetypes.ListValue    `tfsdk:"error_message_regex"`
+	StatusCodes         basetypes.ListValue    `tfsdk:"status_codes"`
+	MaxAttempts         basetypes.Int64Value   `tfsdk:"max_attempts"`
+	InitialDelay        basetypes.StringValue  `tfsdk:"initial_delay"`
+	MaxDelay            basetypes.StringValue  `tfsdk:"max_delay"`
+	Multiplier          basetypes.Float64Value `tfsdk:"multiplier"`
+	ResponseIsRetryable basetypes.StringValue  `tfsdk:"response_is_retryable"`
+	state               attr.ValueState
 }
 
 func (v Value) ToTerraformValue(ctx context.Context) (tftypes.Value, error) {
-	attrTypes := make(map[string]tftypes.Type, 5)
+	attrTypes := make(map[string]tftypes.Type, 7)
 
 	var val tftypes.Value
 	var err error
@@ -185,12 +306,20 @@ func (v Value) ToTerraformValue(ctx context.Context) (tftypes.Value, error) {
 	attrTypes["error_message_regex"] = basetypes.ListType{
 		ElemType: types.StringType,
 	}.TerraformType(ctx)
+	attrTypes["status_codes"] = basetypes.ListType{
+		ElemType: types.Int64Type,
+	}.TerraformType(ctx)
+	attrTypes["max_attempts"] = basetypes.Int64Type{}.TerraformType(ctx)
+	attrTypes["initial_delay"] = basetypes.StringType{}.TerraformType(ctx)
+	attrTypes["max_delay"] = basetypes.StringType{}.TerraformType(ctx)
+	attrTypes["multiplier"] = basetypes.Float64Type{}.TerraformType(ctx)
+	attrTypes["response_is_retryable"] = basetypes.StringType{}.TerraformType(ctx)
 
 	objectType := tftypes.Object{AttributeTypes: attrTypes}
 
 	switch v.state {
 	case attr.ValueStateKnown:
-		vals := make(map[string]tftypes.Value, 5)
+		vals := make(map[string]tftypes.Value, 7)
 
 		val, err = v.ErrorMessageRegex.ToTerraformValue(ctx)
 		if err != nil {
@@ -199,6 +328,48 @@ func (v Value) ToTerraformValue(ctx context.Context) (tftypes.Value, error) {
 
 		vals["error_message_regex"] = val
 
+		val, err = v.StatusCodes.ToTerraformValue(ctx)
+		if err != nil {
+			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
+		}
+
+		vals["status_codes"] = val
+
+		val, err = v.MaxAttempts.ToTerraformValue(ctx)
+		if err != nil {
+			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
+		}
+
+		vals["max_attempts"] = val
+
+		val, err = v.InitialDelay.ToTerraformValue(ctx)
+		if err != nil {
+			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
+		}
+
+		vals["initial_delay"] = val
+
+		val, err = v.MaxDelay.ToTerraformValue(ctx)
+		if err != nil {
+			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
+		}
+
+		vals["max_delay"] = val
+
+		val, err = v.Multiplier.ToTerraformValue(ctx)
+		if err != nil {
+			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
+		}
+
+		vals["multiplier"] = val
+
+		val, err = v.ResponseIsRetryable.ToTerraformValue(ctx)
+		if err != nil {
+			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
+		}
+
+		vals["response_is_retryable"] = val
+
 		if err := tftypes.ValidateValue(objectType, vals); err != nil {
 			return tftypes.NewValue(objectType, tftypes.UnknownValue), err
 		}
@@ -245,6 +416,42 @@ func (v Value) ToObjectValue(ctx context.Context) (basetypes.ObjectValue, diag.D
 			"error_message_regex": basetypes.ListType{
 				ElemType: types.StringType,
 			},
+			"status_codes": basetypes.ListType{
+				ElemType: types.Int64Type,
+			},
+			"max_attempts":          basetypes.Int64Type{},
+			"initial_delay":         basetypes.StringType{},
+			"max_delay":             basetypes.StringType{},
+			"multiplier":            basetypes.Float64Type{},
+			"response_is_retryable": basetypes.StringType{},
+		}), diags
+	}
+
+	var statusCodesVal basetypes.ListValue
+	switch {
+	case v.StatusCodes.IsUnknown():
+		statusCodesVal = types.ListUnknown(types.Int64Type)
+	case v.StatusCodes.IsNull():
+		statusCodesVal = types.ListNull(types.Int64Type)
+	default:
+		var d diag.Diagnostics
+		statusCodesVal, d = types.ListValue(types.Int64Type, v.StatusCodes.Elements())
+		diags.Append(d...)
+	}
+
+	if diags.HasError() {
+		return types.ObjectUnknown(map[string]attr.Type{
+			"error_message_regex": basetypes.ListType{
+				ElemType: types.StringType,
+			},
+			"status_codes": basetypes.ListType{
+				ElemType: types.Int64Type,
+			},
+			"max_attempts":          basetypes.Int64Type{},
+			"initial_delay":         basetypes.StringType{},
+			"max_delay":             basetypes.StringType{},
+			"multiplier":            basetypes.Float64Type{},
+			"response_is_retryable": basetypes.StringType{},
 		}), diags
 	}
 
@@ -252,6 +459,14 @@ func (v Value) ToObjectValue(ctx context.Context) (basetypes.ObjectValue, diag.D
 		"error_message_regex": basetypes.ListType{
 			ElemType: types.StringType,
 		},
+		"status_codes": basetypes.ListType{
+			ElemType: types.Int64Type,
+		},
+		"max_attempts":          basetypes.Int64Type{},
+		"initial_delay":         basetypes.StringType{},
+		"max_delay":             basetypes.StringType{},
+		"multiplier":            basetypes.Float64Type{},
+		"response_is_retryable": basetypes.StringType{},
 	}
 
 	if v.IsNull() {
@@ -265,7 +480,13 @@ func (v Value) ToObjectValue(ctx context.Context) (basetypes.ObjectValue, diag.D
 	objVal, diags := types.ObjectValue(
 		attributeTypes,
 		map[string]attr.Value{
-			"error_message_regex": errorMessageRegexVal,
+			"error_message_regex":   errorMessageRegexVal,
+			"status_codes":          statusCodesVal,
+			"max_attempts":          v.MaxAttempts,
+			"initial_delay":         v.InitialDelay,
+			"max_delay":             v.MaxDelay,
+			"multiplier":            v.Multiplier,
+			"response_is_retryable": v.ResponseIsRetryable,
 		})
 
 	return objVal, diags
@@ -290,6 +511,30 @@ func (v Value) Equal(o attr.Value) bool {
 		return false
 	}
 
+	if !v.StatusCodes.Equal(other.StatusCodes) {
+		return false
+	}
+
+	if !v.MaxAttempts.Equal(other.MaxAttempts) {
+		return false
+	}
+
+	if !v.InitialDelay.Equal(other.InitialDelay) {
+		return false
+	}
+
+	if !v.MaxDelay.Equal(other.MaxDelay) {
+		return false
+	}
+
+	if !v.Multiplier.Equal(other.Multiplier) {
+		return false
+	}
+
+	if !v.ResponseIsRetryable.Equal(other.ResponseIsRetryable) {
+		return false
+	}
+
 	return true
 }
 
@@ -306,6 +551,14 @@ func (v Value) AttributeTypes(ctx context.Context) map[string]attr.Type {
 		"error_message_regex": basetypes.ListType{
 			ElemType: types.StringType,
 		},
+		"status_codes": basetypes.ListType{
+			ElemType: types.Int64Type,
+		},
+		"max_attempts":          basetypes.Int64Type{},
+		"initial_delay":         basetypes.StringType{},
+		"max_delay":             basetypes.StringType{},
+		"multiplier":            basetypes.Float64Type{},
+		"response_is_retryable": basetypes.StringType{},
 	}
 }
 
@@ -334,3 +587,57 @@ func (v Value) GetErrorMessagesRegex() []regexp.Regexp {
 	}
 	return res
 }
+
+func (v Value) GetStatusCodes() []int {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	res := make([]int, 0, len(v.StatusCodes.Elements()))
+	for _, elem := range v.StatusCodes.Elements() {
+		res = append(res, int(elem.(types.Int64).ValueInt64()))
+	}
+	return res
+}
+
+// GetMaxAttempts returns the maximum number of attempts including the first one, 0 means unlimited.
+func (v Value) GetMaxAttempts() int {
+	if v.IsNull() || v.IsUnknown() {
+		return 0
+	}
+	return int(v.MaxAttempts.ValueInt64())
+}
+
+// GetInitialDelay returns the delay before the first retry, 0 means the default delay.
+func (v Value) GetInitialDelay() time.Duration {
+	return parseDuration(v, v.InitialDelay)
+}
+
+// GetMaxDelay returns the maximum delay between the retries, 0 means the default maximum delay.
+func (v Value) GetMaxDelay() time.Duration {
+	return parseDuration(v, v.MaxDelay)
+}
+
+// GetMultiplier returns the factor which the delay is multiplied by after each retry, 0 means the default exponential backoff.
+func (v Value) GetMultiplier() float64 {
+	if v.IsNull() || v.IsUnknown() {
+		return 0
+	}
+	return v.Multiplier.ValueFloat64()
+}
+
+// GetResponseIsRetryable returns the JMESPath expression which is evaluated against the error response body.
+func (v Value) GetResponseIsRetryable() string {
+	if v.IsNull() || v.IsUnknown() {
+		return ""
+	}
+	return v.ResponseIsRetryable.ValueString()
+}
+
+func parseDuration(v Value, value basetypes.StringValue) time.Duration {
+	if v.IsNull() || v.IsUnknown() || value.ValueString() == "" {
+		return 0
+	}
+	// the value is validated by the schema
+	d, _ := time.ParseDuration(value.ValueString())
+	return d
+}
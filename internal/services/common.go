@@ -3,11 +3,18 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
 	"github.com/microsoft/terraform-provider-msgraph/internal/dynamic"
+	"github.com/microsoft/terraform-provider-msgraph/internal/utils"
 )
 
 func AsMapOfString(input types.Map) map[string]string {
@@ -50,3 +57,66 @@ func unmarshalBody(input types.Dynamic, out interface{}) error {
 	}
 	return nil
 }
+
+// responseErrorDetail returns the detail of a diagnostic for an error returned by the client. The Graph error envelope is
+// summarised with the request IDs and a remediation hint, other errors are returned as they are.
+func responseErrorDetail(err error) string {
+	var batchErr *clients.BatchError
+	if errors.As(err, &batchErr) {
+		details := make([]string, 0, len(batchErr.Failures))
+		for _, failure := range batchErr.Failures {
+			details = append(details, fmt.Sprintf("%s %s: %s", failure.Request.Method, failure.Request.Url, responseErrorDetail(failure.Err)))
+		}
+		return fmt.Sprintf("%d of the batch requests failed:\n\n%s", len(batchErr.Failures), strings.Join(details, "\n\n"))
+	}
+	if graphErr, ok := utils.ParseGraphError(err); ok {
+		return graphErr.String()
+	}
+	return err.Error()
+}
+
+// startSpan starts a span of an operation of a resource or data source, e.g. `msgraph_resource.Create`. The returned function
+// ends the span, it's marked as failed if the diagnostics contain errors.
+func startSpan(ctx context.Context, client *clients.MSGraphClient, name string, url string, apiVersion string) (context.Context, func(*diag.Diagnostics)) {
+	if client == nil {
+		return ctx, func(*diag.Diagnostics) {}
+	}
+	ctx, end := client.StartSpan(ctx, name, url, apiVersion)
+	return ctx, func(diags *diag.Diagnostics) {
+		var err error
+		if diags.HasError() {
+			summaries := make([]string, 0)
+			for _, d := range diags.Errors() {
+				summaries = append(summaries, d.Summary())
+			}
+			err = errors.New(strings.Join(summaries, "; "))
+		}
+		end(err)
+	}
+}
+
+// planProviderDefaults sets the attributes which aren't configured to the defaults of the provider, the attributes are
+// `api_version` and, if ignoreMissingProperty is true, `ignore_missing_property`. It must run before the plan is read from the response.
+func planProviderDefaults(ctx context.Context, client *clients.MSGraphClient, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse, ignoreMissingProperty bool) {
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+	var defaults clients.Defaults
+	if client != nil {
+		defaults = client.Defaults
+	}
+
+	var apiVersion types.String
+	response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("api_version"), &apiVersion)...)
+	if apiVersion.IsNull() {
+		response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("api_version"), defaults.ApiVersionOr(""))...)
+	}
+
+	if ignoreMissingProperty {
+		var value types.Bool
+		response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("ignore_missing_property"), &value)...)
+		if value.IsNull() {
+			response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("ignore_missing_property"), defaults.IgnoreMissingPropertyOr(true))...)
+		}
+	}
+}
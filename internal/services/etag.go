@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+	"github.com/microsoft/terraform-provider-msgraph/internal/utils"
+)
+
+const (
+	// FlagETag is the private state key which stores the `@odata.etag` of the last read.
+	FlagETag = "etag"
+
+	ETagModeDisabled = "disabled"
+	ETagModeStrict   = "strict"
+	ETagModeRefresh  = "refresh"
+)
+
+var ETagModes = []string{ETagModeDisabled, ETagModeStrict, ETagModeRefresh}
+
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func etagEnabled(mode string) bool {
+	return mode == ETagModeStrict || mode == ETagModeRefresh
+}
+
+// etagFromBody returns the `@odata.etag` of the response body.
+func etagFromBody(body interface{}) string {
+	if bodyMap, ok := body.(map[string]interface{}); ok {
+		if etag, ok := bodyMap["@odata.etag"].(string); ok {
+			return etag
+		}
+	}
+	return ""
+}
+
+// setETag stores the `@odata.etag` of the response body in the private state if the etag is enabled, otherwise it removes it.
+// The values of the private state must be valid JSON, so the etag, e.g. `W/"abc"`, is stored as a JSON string.
+func setETag(ctx context.Context, private privateStateSetter, mode string, body interface{}) diag.Diagnostics {
+	etag := etagFromBody(body)
+	if !etagEnabled(mode) || etag == "" {
+		// a nil value removes the key
+		return private.SetKey(ctx, FlagETag, nil)
+	}
+	data, err := json.Marshal(etag)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to store the etag", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, FlagETag, data)
+}
+
+func getETag(ctx context.Context, private privateStateGetter) string {
+	v, _ := private.GetKey(ctx, FlagETag)
+	var etag string
+	if len(v) == 0 || json.Unmarshal(v, &etag) != nil {
+		return ""
+	}
+	return etag
+}
+
+// withIfMatch returns a copy of the request options with the If-Match header if the etag is enabled and known.
+func withIfMatch(options clients.RequestOptions, mode string, etag string) clients.RequestOptions {
+	if !etagEnabled(mode) || etag == "" {
+		return options
+	}
+	headers := make(map[string]string, len(options.Headers)+1)
+	for key, value := range options.Headers {
+		headers[key] = value
+	}
+	headers["If-Match"] = etag
+	options.Headers = headers
+	return options
+}
+
+func preconditionFailed(err error) bool {
+	return utils.ResponseErrorWasStatusCode(err, http.StatusPreconditionFailed)
+}
+
+// refreshPatch recomputes the patch after the resource was modified by others. It contains the changes of the plan, and
+// the configured properties whose remote values diverged from the configuration.
+func refreshPatch(patchBody interface{}, requestBody interface{}, latest interface{}) interface{} {
+	remote := utils.UpdateObject(requestBody, latest, utils.UpdateJsonOption{IgnoreMissingProperty: true})
+	drift := utils.DiffObject(remote, requestBody, utils.UpdateJsonOption{})
+	if utils.IsEmptyObject(drift) {
+		return patchBody
+	}
+	if utils.IsEmptyObject(patchBody) {
+		return drift
+	}
+	return utils.MergeObject(patchBody, drift)
+}
+
+const preconditionFailedDetail = "The resource was modified after it was last read, so the request was rejected to avoid overwriting the changes. " +
+	"Run `terraform apply` again to review the latest changes, or set `etag_mode` to `refresh` to re-read the resource and retry automatically."
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type privateState interface {
+	privateStateGetter
+	privateStateSetter
+}
+
+// newPrivateState returns an empty private state of the framework, which only accepts valid JSON values.
+func newPrivateState() privateState {
+	var resp resource.ReadResponse
+	private := reflect.ValueOf(&resp.Private).Elem()
+	private.Set(reflect.New(private.Type().Elem()))
+	return resp.Private
+}
+
+func TestSetETag(t *testing.T) {
+	testcases := []struct {
+		name string
+		mode string
+		body interface{}
+		want string
+	}{
+		{name: "weak etag", mode: ETagModeStrict, body: map[string]interface{}{"@odata.etag": `W/"abc"`}, want: `W/"abc"`},
+		{name: "refresh", mode: ETagModeRefresh, body: map[string]interface{}{"@odata.etag": `"abc"`}, want: `"abc"`},
+		{name: "disabled", mode: ETagModeDisabled, body: map[string]interface{}{"@odata.etag": `W/"abc"`}, want: ""},
+		{name: "not set", mode: "", body: map[string]interface{}{"@odata.etag": `W/"abc"`}, want: ""},
+		{name: "no etag", mode: ETagModeStrict, body: map[string]interface{}{"id": "1"}, want: ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			private := newPrivateState()
+			if diags := setETag(ctx, private, tc.mode, tc.body); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if got := getETag(ctx, private); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetETag_Removes(t *testing.T) {
+	ctx := context.Background()
+	private := newPrivateState()
+	if diags := setETag(ctx, private, ETagModeStrict, map[string]interface{}{"@odata.etag": `W/"abc"`}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := setETag(ctx, private, ETagModeDisabled, map[string]interface{}{"@odata.etag": `W/"def"`}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if got := getETag(ctx, private); got != "" {
+		t.Errorf("got %q, want the etag to be removed", got)
+	}
+}
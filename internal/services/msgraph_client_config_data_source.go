@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MSGraphClientConfigDataSource{}
+
+func NewMSGraphClientConfigDataSource() datasource.DataSource {
+	return &MSGraphClientConfigDataSource{}
+}
+
+// MSGraphClientConfigDataSource defines the data source implementation.
+type MSGraphClientConfigDataSource struct {
+	client *clients.Client
+}
+
+// MSGraphClientConfigDataSourceModel describes the data source data model.
+type MSGraphClientConfigDataSourceModel struct {
+	Id                types.String   `tfsdk:"id"`
+	TenantId          types.String   `tfsdk:"tenant_id"`
+	ClientId          types.String   `tfsdk:"client_id"`
+	ObjectId          types.String   `tfsdk:"object_id"`
+	IdentityType      types.String   `tfsdk:"identity_type"`
+	UserPrincipalName types.String   `tfsdk:"user_principal_name"`
+	Roles             types.List     `tfsdk:"roles"`
+	Scopes            types.List     `tfsdk:"scopes"`
+	Credential        types.String   `tfsdk:"credential"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *MSGraphClientConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_client_config"
+}
+
+func (r *MSGraphClientConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "This data source exposes the principal which the provider is authenticated as, e.g. to assign the current principal as an owner, or to check the granted permissions up front. " +
+			"The values are decoded from the claims of the access token for Microsoft Graph.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The object ID of the authenticated principal.",
+				Computed:            true,
+			},
+
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the tenant which the provider is authenticated to.",
+				Computed:            true,
+			},
+
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "The client ID of the application which obtained the access token, e.g. the app ID of a service principal, or the Azure CLI for a user.",
+				Computed:            true,
+			},
+
+			"object_id": schema.StringAttribute{
+				MarkdownDescription: "The object ID of the authenticated user, service principal or managed identity.",
+				Computed:            true,
+			},
+
+			"identity_type": schema.StringAttribute{
+				MarkdownDescription: "The type of the authenticated principal. The possible values are `user`, `service_principal` and `managed_identity`.",
+				Computed:            true,
+			},
+
+			"user_principal_name": schema.StringAttribute{
+				MarkdownDescription: "The user principal name of the authenticated user. It's empty for service principals and managed identities.",
+				Computed:            true,
+			},
+
+			"roles": schema.ListAttribute{
+				MarkdownDescription: "The application permissions granted to a service principal or managed identity, e.g. `Application.ReadWrite.All`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "The delegated permissions granted to the application on behalf of a user, e.g. `User.Read`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+
+			"credential": schema.StringAttribute{
+				MarkdownDescription: "The authentication method which issued the access token. The possible values are `oidc`, `azure_pipelines`, `client_secret`, `client_certificate`, `managed_identity`, `azure_cli` and `azure_powershell`. " +
+					"It's empty if the provider is configured with a custom credential.",
+				Computed: true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Read: true,
+			}),
+		},
+	}
+}
+
+func (r *MSGraphClientConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(*clients.Client); ok {
+		r.client = v
+	}
+}
+
+func (r *MSGraphClientConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model MSGraphClientConfigDataSourceModel
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &model)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.client.MSGraphClient.Defaults.Timeout("read", 5*time.Minute))
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	claims, err := r.client.TokenClaims(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to obtain the claims of the access token", err.Error())
+		return
+	}
+
+	model.Id = types.StringValue(claims.ObjectId)
+	model.TenantId = types.StringValue(claims.TenantId)
+	model.ClientId = types.StringValue(claims.ClientId)
+	model.ObjectId = types.StringValue(claims.ObjectId)
+	model.IdentityType = types.StringValue(claims.IdentityType)
+	model.UserPrincipalName = types.StringValue(claims.UserPrincipalName)
+	model.Roles, diags = types.ListValueFrom(ctx, types.StringType, claims.Roles)
+	resp.Diagnostics.Append(diags...)
+	model.Scopes, diags = types.ListValueFrom(ctx, types.StringType, claims.Scopes)
+	resp.Diagnostics.Append(diags...)
+	model.Credential = types.StringValue("")
+	if cred, ok := r.client.Option.Cred.(*clients.ChainedCredential); ok {
+		model.Credential = types.StringValue(cred.Name())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
@@ -0,0 +1,36 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/microsoft/terraform-provider-msgraph/internal/acceptance"
+	"github.com/microsoft/terraform-provider-msgraph/internal/acceptance/check"
+)
+
+type MSGraphTestClientConfigDataSource struct{}
+
+func TestAcc_DataSourceClientConfigBasic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.msgraph_client_config", "test")
+	r := MSGraphTestClientConfigDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.basic(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("tenant_id").IsUUID(),
+				check.That(data.ResourceName).Key("object_id").IsUUID(),
+				check.That(data.ResourceName).Key("client_id").IsUUID(),
+				check.That(data.ResourceName).Key("identity_type").MatchesRegex(regexp.MustCompile(`^(user|service_principal|managed_identity)$`)),
+				check.That(data.ResourceName).Key("credential").IsSet(),
+			),
+		},
+	})
+}
+
+func (r MSGraphTestClientConfigDataSource) basic() string {
+	return `
+data "msgraph_client_config" "test" {
+}`
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -36,6 +37,14 @@ type MSGraphDataSourceModel struct {
 	Headers              types.Map         `tfsdk:"headers"`
 	QueryParameters      types.Map         `tfsdk:"query_parameters"`
 	Retry                retry.Value       `tfsdk:"retry"`
+	MaxItems             types.Int64       `tfsdk:"max_items"`
+	MaxPages             types.Int64       `tfsdk:"max_pages"`
+	PageSize             types.Int64       `tfsdk:"page_size"`
+	Truncated            types.Bool        `tfsdk:"truncated"`
+	RawContent           types.Bool        `tfsdk:"raw_content"`
+	ContentBase64        types.String      `tfsdk:"content_base64"`
+	ContentType          types.String      `tfsdk:"content_type"`
+	ContentSha256        types.String      `tfsdk:"content_sha256"`
 	Output               types.Dynamic     `tfsdk:"output"`
 	Timeouts             timeouts.Value    `tfsdk:"timeouts"`
 }
@@ -85,11 +94,60 @@ func (r *MSGraphDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 					ElemType: types.StringType,
 				},
 				Optional:            true,
-				MarkdownDescription: "A map of query parameters to include in the request",
+				MarkdownDescription: "A map of query parameters to include in the request. The `ConsistencyLevel: eventual` header is sent automatically with advanced queries, e.g. `$search`, `$count=true` or `endsWith` in `$filter`, and the total of `$count=true` can be exported via the `\"@odata.count\"` query in `response_export_values`.",
+			},
+
+			"max_items": schema.Int64Attribute{
+				MarkdownDescription: docstrings.MaxItems(),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"max_pages": schema.Int64Attribute{
+				MarkdownDescription: docstrings.MaxPages(),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"page_size": schema.Int64Attribute{
+				MarkdownDescription: docstrings.PageSize(),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"raw_content": schema.BoolAttribute{
+				MarkdownDescription: "Whether to read the content of the resource as it is instead of JSON, e.g. `users/{id}/photo/$value` or `drives/{drive-id}/items/{item-id}/content`. The content is exported via `content_base64`, `content_type` and `content_sha256`. Defaults to `false`.",
+				Optional:            true,
 			},
 
 			"retry": retry.Schema(ctx),
 
+			"truncated": schema.BoolAttribute{
+				MarkdownDescription: docstrings.Truncated(),
+				Computed:            true,
+			},
+
+			"content_base64": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded content of the resource, it's only set when `raw_content` is `true`.",
+				Computed:            true,
+			},
+
+			"content_type": schema.StringAttribute{
+				MarkdownDescription: "The content type of the resource, it's only set when `raw_content` is `true`.",
+				Computed:            true,
+			},
+
+			"content_sha256": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded SHA-256 hash of the content of the resource, it's only set when `raw_content` is `true`.",
+				Computed:            true,
+			},
+
 			"output": schema.DynamicAttribute{
 				MarkdownDescription: docstrings.Output(),
 				Computed:            true,
@@ -116,24 +174,49 @@ func (r *MSGraphDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "data.msgraph_resource.Read", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.client.Defaults.Timeout("read", 5*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancelRead := context.WithTimeout(ctx, readTimeout)
 	defer cancelRead()
 
-	apiVersion := "v1.0"
-	if model.ApiVersion.ValueString() != "" {
-		apiVersion = model.ApiVersion.ValueString()
-	}
+	apiVersion := r.client.Defaults.ApiVersionOr(model.ApiVersion.ValueString())
 
 	options := clients.RequestOptions{
 		Headers:         AsMapOfString(model.Headers),
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.QueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+
+	model.ContentBase64 = types.StringNull()
+	model.ContentType = types.StringNull()
+	model.ContentSha256 = types.StringNull()
+	if model.RawContent.ValueBool() {
+		content, err := r.client.ReadRaw(ctx, model.Url.ValueString(), apiVersion, options)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
+			return
+		}
+		model.Id = types.StringValue(model.Url.ValueString())
+		model.Truncated = types.BoolValue(false)
+		model.ContentBase64 = types.StringValue(content.Base64())
+		model.ContentType = types.StringValue(content.ContentType)
+		model.ContentSha256 = types.StringValue(content.Hash())
+		model.Output = types.DynamicValue(buildOutputFromBody(content, model.ResponseExportValues))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+		return
+	}
+
+	paging := clients.PagingOptions{
+		MaxItems: int(model.MaxItems.ValueInt64()),
+		MaxPages: int(model.MaxPages.ValueInt64()),
+		PageSize: int(model.PageSize.ValueInt64()),
 	}
-	responseBody, err := r.client.Read(ctx, model.Url.ValueString(), apiVersion, options)
+	responseBody, truncated, err := r.client.ReadWithPaging(ctx, model.Url.ValueString(), apiVersion, options, paging)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read data source", err.Error())
+		resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
 		return
 	}
 
@@ -149,6 +232,7 @@ func (r *MSGraphDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	model.Id = types.StringValue(responseId)
+	model.Truncated = types.BoolValue(truncated)
 	model.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
@@ -55,6 +55,35 @@ func TestAcc_DataSourceList(t *testing.T) {
 	})
 }
 
+func TestAcc_DataSourceListPaging(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.msgraph_resource", "test")
+	r := MSGraphTestDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.listPaging(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("output.value.#").HasValue("1"),
+				check.That(data.ResourceName).Key("truncated").Exists(),
+			),
+		},
+	})
+}
+
+func TestAcc_DataSourceAdvancedQuery(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.msgraph_resource", "test")
+	r := MSGraphTestDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.advancedQuery(data),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("output.count").Exists(),
+			),
+		},
+	})
+}
+
 func TestAcc_DataSourceRetry(t *testing.T) {
 	data := acceptance.BuildTestData(t, "data.msgraph_resource", "test")
 	r := MSGraphTestDataSource{}
@@ -118,6 +147,32 @@ data "msgraph_resource" "test" {
 }`
 }
 
+func (r MSGraphTestDataSource) listPaging(data acceptance.TestData) string {
+	return `
+data "msgraph_resource" "test" {
+  url       = "groups"
+  page_size = 1
+  max_items = 1
+  response_export_values = {
+    value = "value"
+  }
+}`
+}
+
+func (r MSGraphTestDataSource) advancedQuery(data acceptance.TestData) string {
+	return `
+data "msgraph_resource" "test" {
+  url = "groups"
+  query_parameters = {
+    "$count"  = ["true"]
+    "$filter" = ["securityEnabled ne false"]
+  }
+  response_export_values = {
+    count = "\"@odata.count\""
+  }
+}`
+}
+
 func (r MSGraphTestDataSource) withRetry(data acceptance.TestData) string {
 	return `
 data "msgraph_resource" "test" {
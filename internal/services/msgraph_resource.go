@@ -4,23 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
 	"github.com/microsoft/terraform-provider-msgraph/internal/docstrings"
@@ -31,11 +32,15 @@ import (
 
 const FlagMoveState = "move_state"
 
+// FlagCreateIncomplete is the private state key which marks a resource whose create request succeeded, but it wasn't read afterwards.
+const FlagCreateIncomplete = "create_incomplete"
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var (
 	_ resource.Resource                     = &MSGraphResource{}
 	_ resource.ResourceWithImportState      = &MSGraphResource{}
 	_ resource.ResourceWithConfigValidators = &MSGraphResource{}
+	_ resource.ResourceWithValidateConfig   = &MSGraphResource{}
 	_ resource.ResourceWithModifyPlan       = &MSGraphResource{}
 	_ resource.ResourceWithMoveState        = &MSGraphResource{}
 )
@@ -53,22 +58,70 @@ func (r *MSGraphResource) ConfigValidators(ctx context.Context) []resource.Confi
 	return []resource.ConfigValidator{}
 }
 
+func (r *MSGraphResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config *MSGraphResourceModel
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &config)...); resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Url.IsUnknown() || config.CreateMethod.IsUnknown() {
+		return
+	}
+
+	// only the directory objects created in their collections are addressed by the id of their deleted items
+	_, isDirectoryObject := deletedItemTypes[strings.Trim(config.Url.ValueString(), "/")]
+	isDirectoryObject = isDirectoryObject && !createdAtUrl(config)
+	if config.DeleteBehavior.ValueString() == DeleteBehaviorPurge && !isDirectoryObject {
+		resp.Diagnostics.AddAttributeError(path.Root("delete_behavior"), "Invalid delete_behavior",
+			fmt.Sprintf("The resources of %q aren't kept in `directory/deletedItems`, so they can't be purged. `purge` only supports the directory objects created in %s.", config.Url.ValueString(), deletedItemCollections))
+	}
+	if !config.WaitForDeletion.IsNull() && !config.WaitForDeletion.IsUnknown() && !config.DeleteBehavior.IsUnknown() {
+		var waitForDeletion WaitForDeletionModel
+		resp.Diagnostics.Append(config.WaitForDeletion.As(ctx, &waitForDeletion, basetypes.ObjectAsOptions{})...)
+		// the deleted item is only removed from directory/deletedItems by a purge, otherwise it's kept for 30 days
+		if waitForDeletion.IncludeDeletedItems.ValueBool() && config.DeleteBehavior.ValueString() != DeleteBehaviorPurge {
+			resp.Diagnostics.AddAttributeError(path.Root("wait_for_deletion").AtName("include_deleted_items"), "Invalid wait_for_deletion",
+				"A deleted directory object is kept in `directory/deletedItems` for 30 days, so `include_deleted_items` requires `delete_behavior` to be `purge`.")
+		}
+	}
+	if config.RestoreIfSoftDeleted.ValueBool() && !isDirectoryObject {
+		resp.Diagnostics.AddAttributeError(path.Root("restore_if_soft_deleted"), "Invalid restore_if_soft_deleted",
+			fmt.Sprintf("The resources of %q aren't kept in `directory/deletedItems`, so they can't be restored. `restore_if_soft_deleted` only supports the directory objects created in %s.", config.Url.ValueString(), deletedItemCollections))
+	}
+}
+
 // MSGraphResourceModel describes the resource data model.
 type MSGraphResourceModel struct {
-	Id                    types.String      `tfsdk:"id"`
-	ResourceUrl           types.String      `tfsdk:"resource_url"`
-	ApiVersion            types.String      `tfsdk:"api_version"`
-	Url                   types.String      `tfsdk:"url"`
-	Body                  types.Dynamic     `tfsdk:"body"`
-	IgnoreMissingProperty types.Bool        `tfsdk:"ignore_missing_property"`
-	CreateQueryParameters types.Map         `tfsdk:"create_query_parameters"`
-	UpdateQueryParameters types.Map         `tfsdk:"update_query_parameters"`
-	ReadQueryParameters   types.Map         `tfsdk:"read_query_parameters"`
-	DeleteQueryParameters types.Map         `tfsdk:"delete_query_parameters"`
-	ResponseExportValues  map[string]string `tfsdk:"response_export_values"`
-	Retry                 retry.Value       `tfsdk:"retry"`
-	Output                types.Dynamic     `tfsdk:"output"`
-	Timeouts              timeouts.Value    `tfsdk:"timeouts"`
+	Id                       types.String      `tfsdk:"id"`
+	ResourceUrl              types.String      `tfsdk:"resource_url"`
+	ApiVersion               types.String      `tfsdk:"api_version"`
+	Url                      types.String      `tfsdk:"url"`
+	Body                     types.Dynamic     `tfsdk:"body"`
+	SensitiveBody            types.Dynamic     `tfsdk:"sensitive_body"`
+	IgnoreMissingProperty    types.Bool        `tfsdk:"ignore_missing_property"`
+	CreateQueryParameters    types.Map         `tfsdk:"create_query_parameters"`
+	UpdateQueryParameters    types.Map         `tfsdk:"update_query_parameters"`
+	ReadQueryParameters      types.Map         `tfsdk:"read_query_parameters"`
+	DeleteQueryParameters    types.Map         `tfsdk:"delete_query_parameters"`
+	ResponseExportValues     map[string]string `tfsdk:"response_export_values"`
+	Retry                    retry.Value       `tfsdk:"retry"`
+	PollLongRunningOperation types.Bool        `tfsdk:"poll_long_running_operation"`
+	ETagMode                 types.String      `tfsdk:"etag_mode"`
+	ReadUrl                  types.String      `tfsdk:"read_url"`
+	UpdateUrl                types.String      `tfsdk:"update_url"`
+	DeleteUrl                types.String      `tfsdk:"delete_url"`
+	DeleteBody               types.Dynamic     `tfsdk:"delete_body"`
+	CreateMethod             types.String      `tfsdk:"create_method"`
+	UpsertKey                types.String      `tfsdk:"upsert_key"`
+	AdoptExisting            types.Bool        `tfsdk:"adopt_existing"`
+	UpdateMethod             types.String      `tfsdk:"update_method"`
+	DeleteMethod             types.String      `tfsdk:"delete_method"`
+	WaitFor                  types.Object      `tfsdk:"wait_for"`
+	WaitForDeletion          types.Object      `tfsdk:"wait_for_deletion"`
+	DeleteBehavior           types.String      `tfsdk:"delete_behavior"`
+	RestoreIfSoftDeleted     types.Bool        `tfsdk:"restore_if_soft_deleted"`
+	RestoreKey               types.String      `tfsdk:"restore_key"`
+	Output                   types.Dynamic     `tfsdk:"output"`
+	Timeouts                 timeouts.Value    `tfsdk:"timeouts"`
 }
 
 func (r *MSGraphResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -101,7 +154,6 @@ func (r *MSGraphResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Validators: []validator.String{
 					stringvalidator.OneOf("v1.0", "beta"),
 				},
-				Default: stringdefault.StaticString("v1.0"),
 			},
 
 			"body": schema.DynamicAttribute{
@@ -109,11 +161,17 @@ func (r *MSGraphResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Optional:            true,
 			},
 
+			"sensitive_body": schema.DynamicAttribute{
+				MarkdownDescription: docstrings.SensitiveBody(),
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+			},
+
 			"ignore_missing_property": schema.BoolAttribute{
 				MarkdownDescription: docstrings.IgnoreMissingProperty(),
 				Optional:            true,
 				Computed:            true,
-				Default:             booldefault.StaticBool(true),
 			},
 
 			"create_query_parameters": schema.MapAttribute{
@@ -137,7 +195,7 @@ func (r *MSGraphResource) Schema(ctx context.Context, req resource.SchemaRequest
 					ElemType: types.StringType,
 				},
 				Optional:            true,
-				MarkdownDescription: "A mapping of query parameters to be sent with the read request.",
+				MarkdownDescription: "A mapping of query parameters to be sent with the read request. The `ConsistencyLevel: eventual` header is sent automatically with advanced queries, e.g. `$count=true` or `endsWith` in `$filter`.",
 			},
 
 			"delete_query_parameters": schema.MapAttribute{
@@ -154,8 +212,123 @@ func (r *MSGraphResource) Schema(ctx context.Context, req resource.SchemaRequest
 				ElementType:         types.StringType,
 			},
 
+			"poll_long_running_operation": schema.BoolAttribute{
+				MarkdownDescription: docstrings.PollLongRunningOperation(),
+				Optional:            true,
+			},
+
+			"etag_mode": schema.StringAttribute{
+				MarkdownDescription: docstrings.ETagMode(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ETagModes...),
+				},
+			},
+
+			"read_url": schema.StringAttribute{
+				MarkdownDescription: docstrings.OperationUrl("read"),
+				Optional:            true,
+			},
+
+			"update_url": schema.StringAttribute{
+				MarkdownDescription: docstrings.OperationUrl("update"),
+				Optional:            true,
+			},
+
+			"delete_url": schema.StringAttribute{
+				MarkdownDescription: docstrings.OperationUrl("delete"),
+				Optional:            true,
+			},
+
+			"delete_body": schema.DynamicAttribute{
+				MarkdownDescription: "A dynamic attribute that contains the body of the delete request, e.g. the `keyId` of a password removed via `removePassword`. Its string values can reference the `{id}` and `{response.<path>}` placeholders like `delete_url`.",
+				Optional:            true,
+			},
+
+			"create_method": schema.StringAttribute{
+				MarkdownDescription: "The HTTP method of the create request. The allowed values are `POST`, `PUT` and `PATCH`. Defaults to `POST`, which creates the resource in the `url` collection and addresses it at `{url}/{id}`. " +
+					"With `PUT` or `PATCH`, the resource is created at the `url` itself, e.g. `users/{id}/manager/$ref` or `applications(uniqueName='{name}')`, and it's read, updated and deleted at the same URL. Changing it forces a new resource to be created.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(http.MethodPost, http.MethodPut, http.MethodPatch),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = methodOrDefault(req.StateValue, http.MethodPost) != methodOrDefault(req.PlanValue, http.MethodPost)
+					}, "Changing the create method forces a new resource to be created.", "Changing the create method forces a new resource to be created."),
+				},
+			},
+
+			"upsert_key": schema.StringAttribute{
+				MarkdownDescription: "The name of the property in `body` which uniquely identifies the resource, e.g. `uniqueName`. When it's set, the resource is upserted via `PATCH {url}({upsert_key}='{value}')` with the `Prefer: create-if-missing` header, " +
+					"so an existing resource with the same key, e.g. one created by an interrupted run, is updated and adopted into the state instead of creating a duplicate. It conflicts with `create_method`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("create_method")),
+				},
+			},
+
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to look up the resource by `upsert_key` via `$filter` before creating it, and adopt an existing resource into the state by updating it with `body`. " +
+					"Use it for the resources which don't support upserts by an alternate key. The adopted resource is deleted when it's destroyed. Defaults to `false`.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("upsert_key")),
+				},
+			},
+
+			"update_method": schema.StringAttribute{
+				MarkdownDescription: "The HTTP method of the update request. The allowed values are `PATCH` and `PUT`. Defaults to `PATCH`, which only sends the changed properties. With `PUT`, the whole `body` is sent.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(http.MethodPatch, http.MethodPut),
+				},
+			},
+
+			"delete_method": schema.StringAttribute{
+				MarkdownDescription: "The HTTP method of the delete request. The allowed values are `DELETE` and `POST`. Defaults to `DELETE`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(http.MethodDelete, http.MethodPost),
+				},
+			},
+
 			"retry": retry.Schema(ctx),
 
+			"wait_for": waitForSchema("The URL which is polled, e.g. `servicePrincipals(appId='{response.appId}')`. It supports the same placeholders as `read_url`. Defaults to the URL which the resource is read at."),
+
+			"wait_for_deletion": waitForDeletionSchema(),
+
+			"delete_behavior": schema.StringAttribute{
+				MarkdownDescription: docstrings.DeleteBehavior(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(DeleteBehaviors...),
+				},
+			},
+
+			"restore_if_soft_deleted": schema.BoolAttribute{
+				MarkdownDescription: "Whether to restore a matching item from `directory/deletedItems` instead of creating a new resource, and update it with `body`. " +
+					"The deleted item is matched by `restore_key` if it's set, otherwise by the `uniqueName` of applications, the `mailNickname` of groups, the `userPrincipalName` of users, " +
+					"the `appId` of service principals and the `displayName` of administrative units. A new resource is created if there's no matching deleted item. " +
+					"It only applies to the collections of these directory objects, and it conflicts with `create_method`. Defaults to `false`.",
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("create_method")),
+				},
+			},
+
+			"restore_key": schema.StringAttribute{
+				MarkdownDescription: "The name of the property in `body` which identifies the deleted item to restore, e.g. `displayName` for an application without `uniqueName`. " +
+					"It only affects the lookup in `directory/deletedItems`, unlike `upsert_key` it doesn't change how the resource is created. It requires `restore_if_soft_deleted`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("restore_if_soft_deleted")),
+				},
+			},
+
 			"output": schema.DynamicAttribute{
 				MarkdownDescription: docstrings.Output(),
 				Computed:            true,
@@ -183,8 +356,12 @@ func (r *MSGraphResource) Configure(ctx context.Context, req resource.ConfigureR
 }
 
 func (r *MSGraphResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if planProviderDefaults(ctx, r.client, request, response, true); response.Diagnostics.HasError() {
+		return
+	}
+
 	var plan *MSGraphResourceModel
-	if response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
+	if response.Diagnostics.Append(response.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
 		return
 	}
 
@@ -193,9 +370,35 @@ func (r *MSGraphResource) ModifyPlan(ctx context.Context, request resource.Modif
 		return
 	}
 
-	if plan == nil || state == nil {
+	if plan == nil {
+		return
+	}
+
+	var sensitiveValue types.Dynamic
+	if response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("sensitive_body"), &sensitiveValue)...); response.Diagnostics.HasError() {
 		return
 	}
+	var sensitive, body interface{}
+	if err := unmarshalBody(sensitiveValue, &sensitive); err != nil {
+		response.Diagnostics.AddError("Invalid sensitive_body", fmt.Sprintf(`The argument "sensitive_body" is invalid: %s`, err.Error()))
+		return
+	}
+	if err := unmarshalBody(plan.Body, &body); err == nil {
+		if paths := overlappingProperties(body, sensitive, ""); len(paths) != 0 {
+			response.Diagnostics.AddAttributeError(path.Root("sensitive_body"), "Invalid sensitive_body",
+				fmt.Sprintf("The properties %s are set in both `body` and `sensitive_body`, they must only be set in one of them.", strings.Join(paths, ", ")))
+			return
+		}
+	}
+
+	if state == nil {
+		return
+	}
+
+	// the write-only sensitive_body isn't in the state, so the resource is updated when its hash changes
+	if sensitiveValue.IsUnknown() || sensitiveValue.IsUnderlyingValueUnknown() || sensitiveBodyChanged(ctx, request.Private, sensitive) {
+		response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("output"), types.DynamicUnknown())...)
+	}
 
 	if strings.Contains(plan.Url.ValueString(), "/$ref") {
 		if !dynamic.SemanticallyEqual(plan.Body, state.Body) {
@@ -205,7 +408,16 @@ func (r *MSGraphResource) ModifyPlan(ctx context.Context, request resource.Modif
 			response.RequiresReplace.Append(path.Root("response_export_values"))
 		}
 		if !reflect.DeepEqual(plan.ApiVersion, state.ApiVersion) {
-			response.RequiresReplace.Append(path.Root("api_version"))
+			var apiVersion types.String
+			if response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("api_version"), &apiVersion)...); response.Diagnostics.HasError() {
+				return
+			}
+			if apiVersion.IsNull() {
+				// the change only comes from the default api version of the provider, the reference is kept as it is
+				response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("api_version"), state.ApiVersion)...)
+			} else {
+				response.RequiresReplace.Append(path.Root("api_version"))
+			}
 		}
 	}
 }
@@ -216,7 +428,10 @@ func (r *MSGraphResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	createTimeout, diags := model.Timeouts.Create(ctx, 30*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource.Create", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	createTimeout, diags := model.Timeouts.Create(ctx, r.client.Defaults.Timeout("create", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, createTimeout)
 	defer cancel()
@@ -226,16 +441,41 @@ func (r *MSGraphResource) Create(ctx context.Context, req resource.CreateRequest
 		resp.Diagnostics.AddError("Failed to unmarshal body", err.Error())
 		return
 	}
+	sensitive, diags := sensitiveBody(ctx, req.Config)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	if sensitive != nil {
+		requestBody = utils.MergeObject(requestBody, sensitive)
+	}
 
 	options := clients.RequestOptions{
-		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.CreateQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		QueryParameters:          clients.NewQueryParameters(AsMapOfLists(model.CreateQueryParameters)),
+		RetryOptions:             r.client.RetryOptions(model.Retry),
+		PollLongRunningOperation: model.PollLongRunningOperation.ValueBool(),
+	}
+	var responseBody interface{}
+	var err error
+	if model.RestoreIfSoftDeleted.ValueBool() {
+		responseBody, err = r.restoreSoftDeleted(ctx, model, requestBody, options)
+	}
+	// the resource is created unless a deleted item was restored
+	if err == nil && responseBody == nil {
+		if !model.UpsertKey.IsNull() {
+			responseBody, err = r.upsert(ctx, model, requestBody, options)
+		} else if createMethod := methodOrDefault(model.CreateMethod, http.MethodPost); createMethod == http.MethodPost {
+			responseBody, err = r.client.Create(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, options)
+		} else {
+			responseBody, err = r.client.Action(ctx, createMethod, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, options)
+		}
 	}
-	responseBody, err := r.client.Create(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, options)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create resource", err.Error())
+		resp.Diagnostics.AddError("Failed to create resource", responseErrorDetail(err))
 		return
 	}
+	resp.Diagnostics.Append(setSensitiveBodyHash(ctx, resp.Private, sensitive)...)
+	values := templateValues(urlTemplates(model), responseBody)
+	resp.Diagnostics.Append(setTemplateValues(ctx, resp.Private, values)...)
 
 	if strings.HasSuffix(model.Url.ValueString(), "/$ref") { // extract the id from the response body
 		if requestMap, ok := requestBody.(map[string]interface{}); ok {
@@ -261,25 +501,77 @@ func (r *MSGraphResource) Create(ctx context.Context, req resource.CreateRequest
 			}
 		}
 
+		// the resource created in a collection is addressed by its id, e.g. the response of `204 No Content` doesn't have it
+		if responseId == "" && !createdAtUrl(model) && model.ReadUrl.IsNull() {
+			resp.Diagnostics.AddError("Failed to create resource", fmt.Sprintf("The response of the create request to %q doesn't contain the `id` of the resource, so it can't be addressed. "+
+				"If the resource is created at its own URL, set `create_method` to `PUT` or `PATCH`, otherwise set `read_url` to the URL it's read at.", model.Url.ValueString()))
+			return
+		}
+
 		model.Id = types.StringValue(responseId)
-		model.ResourceUrl = types.StringValue(fmt.Sprintf("%s/%s", model.Url.ValueString(), responseId))
+		itemUrl, err := operationUrl(model.ReadUrl, model, values)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid read_url", err.Error())
+			return
+		}
+		model.ResourceUrl = types.StringValue(itemUrl)
+
+		// the resource exists from now on, so it's saved before it's read. If the read fails, e.g. because of replication delays,
+		// the resource is tainted instead of orphaned, and the next apply replaces it instead of creating a duplicate.
+		partial := *model
+		if partial.Id.ValueString() == "" {
+			partial.Id = types.StringValue(itemUrl)
+		}
+		partial.Output = types.DynamicNull()
+		resp.Diagnostics.Append(resp.State.Set(ctx, &partial)...)
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, FlagCreateIncomplete, []byte("true"))...)
+
 		options = clients.RequestOptions{
 			QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
 			RetryOptions: clients.CombineRetryOptions(
 				clients.NewRetryOptionsForReadAfterCreate(),
-				clients.NewRetryOptions(model.Retry),
+				r.client.RetryOptions(model.Retry),
 			),
 		}
-		responseBody, err = r.client.Read(ctx, fmt.Sprintf("%s/%s", model.Url.ValueString(), model.Id.ValueString()), model.ApiVersion.ValueString(), options)
+		responseBody, err = r.client.Read(ctx, itemUrl, model.ApiVersion.ValueString(), options)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to read data source", err.Error())
+			resp.Diagnostics.AddError("Failed to read data source", fmt.Sprintf("The resource %q was created, but it couldn't be read. It's saved in the state as tainted, "+
+				"so the next apply replaces it.\n\n%s", itemUrl, responseErrorDetail(err)))
 			return
 		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, FlagCreateIncomplete, nil)...)
+		resp.Diagnostics.Append(setETag(ctx, resp.Private, model.ETagMode.ValueString(), responseBody)...)
+		if responseId == "" {
+			if model.ReadUrl.IsNull() {
+				model.Id = types.StringValue(idFromBody(responseBody, utils.LastSegment(model.Url.ValueString())))
+			} else {
+				// the resource is read via another resource, e.g. its parent, so the read_url identifies it
+				model.Id = types.StringValue(itemUrl)
+			}
+		}
 	}
 
 	model.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+
+	// the resource is saved before the wait, so it's tainted instead of orphaned if the condition isn't met
+	if err := r.waitFor(ctx, model, values); err != nil {
+		resp.Diagnostics.AddError("Failed to wait for the condition of wait_for", fmt.Sprintf("The resource %q was created, but the condition of `wait_for` wasn't met. "+
+			"It's saved in the state as tainted, so the next apply replaces it.\n\n%s", model.ResourceUrl.ValueString(), responseErrorDetail(err)))
+	}
+}
+
+// waitFor waits for the condition of wait_for, its URL supports the same placeholders as read_url.
+func (r *MSGraphResource) waitFor(ctx context.Context, model *MSGraphResourceModel, values map[string]string) error {
+	expandUrl := func(template string) (string, error) {
+		return expandTemplate(template, model.Id.ValueString(), values)
+	}
+	options := clients.RequestOptions{
+		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+	return waitFor(ctx, r.client, model.WaitFor, model.ResourceUrl.ValueString(), expandUrl, model.ApiVersion.ValueString(), options)
 }
 
 func (r *MSGraphResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -287,11 +579,14 @@ func (r *MSGraphResource) Update(ctx context.Context, req resource.UpdateRequest
 	if resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...); resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource.Update", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
 	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
 		return
 	}
 
-	updateTimeout, diags := model.Timeouts.Update(ctx, 30*time.Minute)
+	updateTimeout, diags := model.Timeouts.Update(ctx, r.client.Defaults.Timeout("update", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
 	defer cancel()
@@ -306,40 +601,96 @@ func (r *MSGraphResource) Update(ctx context.Context, req resource.UpdateRequest
 		resp.Diagnostics.AddError("Invalid body in prior state", fmt.Sprintf(`The state "body" is invalid: %s`, err.Error()))
 		return
 	}
+	sensitive, diags := sensitiveBody(ctx, req.Config)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+	if sensitive != nil {
+		requestBody = utils.MergeObject(requestBody, sensitive)
+	}
 
 	diffOption := utils.UpdateJsonOption{
 		IgnoreCasing:          false,
 		IgnoreMissingProperty: false,
 		IgnoreNullProperty:    false,
+		WriteOnly:             sensitive,
 	}
 	patchBody := utils.DiffObject(previousBody, requestBody, diffOption)
+	// the sensitive_body isn't in the prior state, so it's only sent when it changed
+	if sensitiveBodyChanged(ctx, req.Private, sensitive) {
+		patchBody = utils.MergeObject(patchBody, sensitive)
+	}
+
+	values := getTemplateValues(ctx, req.Private)
+	readUrl, err := operationUrl(model.ReadUrl, model, values)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid read_url", err.Error())
+		return
+	}
+	updateUrl, err := operationUrl(model.UpdateUrl, model, values)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid update_url", err.Error())
+		return
+	}
+	readOptions := clients.RequestOptions{
+		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+	updateMethod := methodOrDefault(model.UpdateMethod, http.MethodPatch)
+	update := func(patchBody interface{}, options clients.RequestOptions) error {
+		// PUT replaces the resource, so the whole body is sent
+		if updateMethod == http.MethodPut {
+			_, err := r.client.Action(ctx, http.MethodPut, updateUrl, model.ApiVersion.ValueString(), requestBody, options)
+			return err
+		}
+		_, err := r.client.Update(ctx, updateUrl, model.ApiVersion.ValueString(), patchBody, options)
+		return err
+	}
 
-	// If there's something to update, send PATCH
+	// If there's something to update, send PATCH or PUT
 	if !utils.IsEmptyObject(patchBody) {
 		options := clients.RequestOptions{
-			QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.UpdateQueryParameters)),
-			RetryOptions:    clients.NewRetryOptions(model.Retry),
+			QueryParameters:          clients.NewQueryParameters(AsMapOfLists(model.UpdateQueryParameters)),
+			RetryOptions:             r.client.RetryOptions(model.Retry),
+			PollLongRunningOperation: model.PollLongRunningOperation.ValueBool(),
+		}
+		etagMode := model.ETagMode.ValueString()
+		err := update(patchBody, withIfMatch(options, etagMode, getETag(ctx, req.Private)))
+		if err != nil && preconditionFailed(err) && etagMode == ETagModeRefresh {
+			tflog.Info(ctx, fmt.Sprintf("Resource %q was modified after it was last read, re-reading it and retrying the update", readUrl))
+			latest, readErr := r.client.Read(ctx, readUrl, model.ApiVersion.ValueString(), readOptions)
+			if readErr != nil {
+				resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(readErr))
+				return
+			}
+			patchBody = refreshPatch(patchBody, requestBody, latest)
+			err = update(patchBody, withIfMatch(options, etagMode, etagFromBody(latest)))
 		}
-		_, err := r.client.Update(ctx, fmt.Sprintf("%s/%s", model.Url.ValueString(), model.Id.ValueString()), model.ApiVersion.ValueString(), patchBody, options)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to create resource", err.Error())
+			if preconditionFailed(err) {
+				resp.Diagnostics.AddError("Failed to update resource", fmt.Sprintf("%s\n\n%s", preconditionFailedDetail, responseErrorDetail(err)))
+				return
+			}
+			resp.Diagnostics.AddError("Failed to update resource", responseErrorDetail(err))
 			return
 		}
+		resp.Diagnostics.Append(setSensitiveBodyHash(ctx, resp.Private, sensitive)...)
 	} else {
 		tflog.Info(ctx, "No changes detected in body, skipping update")
 	}
 
-	options := clients.RequestOptions{
-		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
-	}
-	responseBody, err := r.client.Read(ctx, fmt.Sprintf("%s/%s", model.Url.ValueString(), model.Id.ValueString()), model.ApiVersion.ValueString(), options)
+	responseBody, err := r.client.Read(ctx, readUrl, model.ApiVersion.ValueString(), readOptions)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read data source", err.Error())
+		resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
 		return
 	}
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, model.ETagMode.ValueString(), responseBody)...)
 	model.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+
+	if err := r.waitFor(ctx, model, values); err != nil {
+		resp.Diagnostics.AddError("Failed to wait for the condition of wait_for", responseErrorDetail(err))
+	}
 }
 
 func (r *MSGraphResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -348,21 +699,24 @@ func (r *MSGraphResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource.Read", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
 	// Apply read timeout (default 5m if not configured)
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.client.Defaults.Timeout("read", 5*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
 	if model.ApiVersion.ValueString() == "" {
-		model.ApiVersion = types.StringValue("v1.0")
+		model.ApiVersion = types.StringValue(r.client.Defaults.ApiVersionOr(""))
 	}
 
 	state := model
 	if strings.HasSuffix(model.Url.ValueString(), "/$ref") {
 		if v, _ := req.Private.GetKey(ctx, FlagMoveState); v != nil && string(v) == "true" {
 			body := map[string]string{
-				"@odata.id": fmt.Sprintf("https://graph.microsoft.com/v1.0/directoryObjects/%s", model.Id.ValueString()),
+				"@odata.id": fmt.Sprintf("%s/v1.0/directoryObjects/%s", r.client.GraphBaseUrl(), model.Id.ValueString()),
 			}
 			data, err := json.Marshal(body)
 			if err != nil {
@@ -384,16 +738,31 @@ func (r *MSGraphResource) Read(ctx context.Context, req resource.ReadRequest, re
 	}
 
 	options := clients.NewRequestOptions(nil, AsMapOfLists(model.ReadQueryParameters))
-	responseBody, err := r.client.Read(ctx, fmt.Sprintf("%s/%s", model.Url.ValueString(), model.Id.ValueString()), model.ApiVersion.ValueString(), options)
+	readUrl, err := operationUrl(model.ReadUrl, model, getTemplateValues(ctx, req.Private))
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid read_url", err.Error())
+		return
+	}
+	responseBody, err := r.client.Read(ctx, readUrl, model.ApiVersion.ValueString(), options)
 	if err != nil {
+		if utils.ResponseErrorWasNotFound(err) && createIncomplete(ctx, req.Private) {
+			// the resource may not be replicated yet, it's kept in the state, so it's replaced instead of orphaned
+			tflog.Info(ctx, fmt.Sprintf("Resource %q was created but not found yet - keeping it in state", model.Id.ValueString()))
+			return
+		}
 		if utils.ResponseErrorWasNotFound(err) {
 			tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", model.Id.ValueString()))
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Failed to read data source", err.Error())
+		resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
 		return
 	}
+	if createIncomplete(ctx, req.Private) {
+		// the resource has been read, so it's removed from the state if it's not found from now on
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, FlagCreateIncomplete, nil)...)
+	}
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, model.ETagMode.ValueString(), responseBody)...)
 	state.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
 
 	if v, _ := req.Private.GetKey(ctx, FlagMoveState); v != nil && string(v) == "true" {
@@ -448,27 +817,117 @@ func (r *MSGraphResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	deleteTimeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource.Delete", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	deleteBehavior := model.DeleteBehavior.ValueString()
+	if deleteBehavior == DeleteBehaviorDetach {
+		tflog.Info(ctx, fmt.Sprintf("Resource %q is detached - removing from state without deleting it", model.ResourceUrl.ValueString()))
+		return
+	}
+
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, r.client.Defaults.Timeout("delete", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
 	defer cancel()
 
+	values := getTemplateValues(ctx, req.Private)
 	var itemUrl string
-	if strings.HasSuffix(model.Url.ValueString(), "/$ref") {
+	var err error
+	switch {
+	case !model.DeleteUrl.IsNull():
+		itemUrl, err = operationUrl(model.DeleteUrl, model, values)
+	case strings.HasSuffix(model.Url.ValueString(), "/$ref") && !createdAtUrl(model):
 		itemUrl = strings.ReplaceAll(model.Url.ValueString(), "/$ref", fmt.Sprintf("/%s/$ref", model.Id.ValueString()))
-	} else {
-		itemUrl = fmt.Sprintf("%s/%s", model.Url.ValueString(), model.Id.ValueString())
+	default:
+		itemUrl = resourceItemUrl(model)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid delete_url", err.Error())
+		return
+	}
+
+	var deleteBody interface{}
+	if !model.DeleteBody.IsNull() {
+		if err := unmarshalBody(model.DeleteBody, &deleteBody); err != nil {
+			resp.Diagnostics.AddError("Invalid delete_body", fmt.Sprintf(`The argument "delete_body" is invalid: %s`, err.Error()))
+			return
+		}
+		if deleteBody, err = expandBody(deleteBody, model.Id.ValueString(), values); err != nil {
+			resp.Diagnostics.AddError("Invalid delete_body", err.Error())
+			return
+		}
 	}
 
 	options := clients.RequestOptions{
-		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.DeleteQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		QueryParameters:          clients.NewQueryParameters(AsMapOfLists(model.DeleteQueryParameters)),
+		RetryOptions:             r.client.RetryOptions(model.Retry),
+		PollLongRunningOperation: model.PollLongRunningOperation.ValueBool(),
+	}
+	deleteResource := func(options clients.RequestOptions) error {
+		if method := methodOrDefault(model.DeleteMethod, http.MethodDelete); method == http.MethodPost || deleteBody != nil {
+			_, err := r.client.Action(ctx, method, itemUrl, model.ApiVersion.ValueString(), deleteBody, options)
+			return err
+		}
+		return r.client.Delete(ctx, itemUrl, model.ApiVersion.ValueString(), options)
+	}
+	etagMode := model.ETagMode.ValueString()
+	err = deleteResource(withIfMatch(options, etagMode, getETag(ctx, req.Private)))
+	if err != nil && preconditionFailed(err) && etagMode == ETagModeRefresh {
+		tflog.Info(ctx, fmt.Sprintf("Resource %q was modified after it was last read, re-reading it and retrying the deletion", itemUrl))
+		readOptions := clients.NewRequestOptions(nil, AsMapOfLists(model.ReadQueryParameters))
+		readUrl, urlErr := operationUrl(model.ReadUrl, model, values)
+		if urlErr != nil {
+			resp.Diagnostics.AddError("Invalid read_url", urlErr.Error())
+			return
+		}
+		latest, readErr := r.client.Read(ctx, readUrl, model.ApiVersion.ValueString(), readOptions)
+		if readErr != nil {
+			resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(readErr))
+			return
+		}
+		err = deleteResource(withIfMatch(options, etagMode, etagFromBody(latest)))
+	}
+	if err != nil && utils.ResponseErrorWasNotFound(err) && createIncomplete(ctx, req.Private) {
+		tflog.Info(ctx, fmt.Sprintf("Resource %q was created but never found - removing from state", itemUrl))
+		return
 	}
-	err := r.client.Delete(ctx, itemUrl, model.ApiVersion.ValueString(), options)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to delete resource", err.Error())
+		if preconditionFailed(err) {
+			resp.Diagnostics.AddError("Failed to delete resource", fmt.Sprintf("%s\n\n%s", preconditionFailedDetail, responseErrorDetail(err)))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete resource", responseErrorDetail(err))
 		return
 	}
+
+	if deleteBehavior == DeleteBehaviorPurge {
+		if err := r.purge(ctx, model, clients.RequestOptions{RetryOptions: r.client.RetryOptions(model.Retry)}); err != nil {
+			resp.Diagnostics.AddError("Failed to purge resource", fmt.Sprintf("The resource %q was deleted, but it couldn't be permanently deleted from `directory/deletedItems`.\n\n%s",
+				model.ResourceUrl.ValueString(), responseErrorDetail(err)))
+			return
+		}
+	}
+
+	if err := r.waitForDeletion(ctx, model, values); err != nil {
+		resp.Diagnostics.AddError("Failed to wait for the deletion of the resource", responseErrorDetail(err))
+	}
+}
+
+// waitForDeletion waits for the deletion of the resource if wait_for_deletion is set, the resource is polled at its read URL.
+func (r *MSGraphResource) waitForDeletion(ctx context.Context, model *MSGraphResourceModel, values map[string]string) error {
+	if model.WaitForDeletion.IsNull() || model.WaitForDeletion.IsUnknown() {
+		return nil
+	}
+	readUrl, err := operationUrl(model.ReadUrl, model, values)
+	if err != nil {
+		return err
+	}
+	options := clients.RequestOptions{
+		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+	return waitForDeletion(ctx, r.client, model.WaitForDeletion, readUrl, model.Id.ValueString(), model.ApiVersion.ValueString(), options)
 }
 
 func (r *MSGraphResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -479,10 +938,7 @@ func (r *MSGraphResource) ImportState(ctx context.Context, req resource.ImportSt
 		return
 	}
 
-	apiVersion := "v1.0"
-	if parsedUrl.Query().Get("api-version") != "" {
-		apiVersion = parsedUrl.Query().Get("api-version")
-	}
+	apiVersion := r.client.Defaults.ApiVersionOr(parsedUrl.Query().Get("api-version"))
 
 	if strings.HasSuffix(parsedUrl.Path, "/$ref") {
 		reqIdWithoutRef := strings.TrimSuffix(parsedUrl.Path, "/$ref")
@@ -533,6 +989,8 @@ func (r *MSGraphResource) ImportState(ctx context.Context, req resource.ImportSt
 		ReadQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
 		DeleteQueryParameters: types.MapNull(types.ListType{ElemType: types.StringType}),
 		Retry:                 retry.NewValueNull(),
+		WaitFor:               types.ObjectNull(waitForAttributeTypes()),
+		WaitForDeletion:       types.ObjectNull(waitForDeletionAttributeTypes()),
 		Timeouts: timeouts.Value{
 			Object: types.ObjectNull(map[string]attr.Type{
 				"create": types.StringType,
@@ -545,7 +1003,47 @@ func (r *MSGraphResource) ImportState(ctx context.Context, req resource.ImportSt
 	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
 }
 
+// methodOrDefault returns the configured HTTP method, or the default method if it's not configured.
+func methodOrDefault(method types.String, defaultMethod string) string {
+	if method.IsNull() || method.IsUnknown() || method.ValueString() == "" {
+		return defaultMethod
+	}
+	return method.ValueString()
+}
+
+// createdAtUrl returns whether the resource is created at its own URL via PUT or PATCH, instead of in a collection.
+func createdAtUrl(model *MSGraphResourceModel) bool {
+	return methodOrDefault(model.CreateMethod, http.MethodPost) != http.MethodPost
+}
+
+// resourceItemUrl returns the URL which the resource is read, updated and deleted at.
+func resourceItemUrl(model *MSGraphResourceModel) string {
+	if createdAtUrl(model) {
+		return model.Url.ValueString()
+	}
+	return fmt.Sprintf("%s/%s", model.Url.ValueString(), model.Id.ValueString())
+}
+
+// createIncomplete returns whether the resource was created, but not read afterwards.
+func createIncomplete(ctx context.Context, private privateStateGetter) bool {
+	v, _ := private.GetKey(ctx, FlagCreateIncomplete)
+	return string(v) == "true"
+}
+
+// idFromBody returns the `id` of the response body, or the fallback if it doesn't have one.
+func idFromBody(body interface{}, fallback string) string {
+	if bodyMap, ok := body.(map[string]interface{}); ok {
+		if id, ok := bodyMap["id"].(string); ok && id != "" {
+			return id
+		}
+	}
+	return fallback
+}
+
 func buildOutputFromBody(body interface{}, paths map[string]string) attr.Value {
+	if content, ok := body.(*clients.RawContent); ok {
+		body = content.Properties()
+	}
 	var output interface{}
 	output = make(map[string]interface{})
 	for pathKey, path := range paths {
@@ -647,6 +1145,8 @@ func (r *MSGraphResource) MoveState(ctx context.Context) []resource.StateMover {
 					ReadQueryParameters:   types.MapNull(types.ListType{ElemType: types.StringType}),
 					DeleteQueryParameters: types.MapNull(types.ListType{ElemType: types.StringType}),
 					Retry:                 retry.NewValueNull(),
+					WaitFor:               types.ObjectNull(waitForAttributeTypes()),
+					WaitForDeletion:       types.ObjectNull(waitForDeletionAttributeTypes()),
 					Timeouts: timeouts.Value{
 						Object: types.ObjectNull(map[string]attr.Type{
 							"create": types.StringType,
@@ -8,16 +8,17 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
 	"github.com/microsoft/terraform-provider-msgraph/internal/docstrings"
+	"github.com/microsoft/terraform-provider-msgraph/internal/myvalidator"
 	"github.com/microsoft/terraform-provider-msgraph/internal/retry"
 )
 
@@ -38,18 +39,21 @@ type MSGraphResourceAction struct {
 
 // MSGraphResourceActionModel describes the resource data model.
 type MSGraphResourceActionModel struct {
-	Id                   types.String      `tfsdk:"id"`
-	ApiVersion           types.String      `tfsdk:"api_version"`
-	ResourceUrl          types.String      `tfsdk:"resource_url"`
-	Action               types.String      `tfsdk:"action"`
-	Method               types.String      `tfsdk:"method"`
-	Body                 types.Dynamic     `tfsdk:"body"`
-	QueryParameters      types.Map         `tfsdk:"query_parameters"`
-	Headers              types.Map         `tfsdk:"headers"`
-	ResponseExportValues map[string]string `tfsdk:"response_export_values"`
-	Retry                retry.Value       `tfsdk:"retry"`
-	Output               types.Dynamic     `tfsdk:"output"`
-	Timeouts             timeouts.Value    `tfsdk:"timeouts"`
+	Id                       types.String      `tfsdk:"id"`
+	ApiVersion               types.String      `tfsdk:"api_version"`
+	ResourceUrl              types.String      `tfsdk:"resource_url"`
+	Action                   types.String      `tfsdk:"action"`
+	Method                   types.String      `tfsdk:"method"`
+	Body                     types.Dynamic     `tfsdk:"body"`
+	BodyBase64               types.String      `tfsdk:"body_base64"`
+	ContentType              types.String      `tfsdk:"content_type"`
+	QueryParameters          types.Map         `tfsdk:"query_parameters"`
+	Headers                  types.Map         `tfsdk:"headers"`
+	ResponseExportValues     map[string]string `tfsdk:"response_export_values"`
+	Retry                    retry.Value       `tfsdk:"retry"`
+	PollLongRunningOperation types.Bool        `tfsdk:"poll_long_running_operation"`
+	Output                   types.Dynamic     `tfsdk:"output"`
+	Timeouts                 timeouts.Value    `tfsdk:"timeouts"`
 }
 
 func (r *MSGraphResourceAction) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -104,7 +108,6 @@ func (r *MSGraphResourceAction) Schema(ctx context.Context, req resource.SchemaR
 				Validators: []validator.String{
 					stringvalidator.OneOf("v1.0", "beta"),
 				},
-				Default: stringdefault.StaticString("v1.0"),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -115,6 +118,23 @@ func (r *MSGraphResourceAction) Schema(ctx context.Context, req resource.SchemaR
 				Optional:            true,
 			},
 
+			"body_base64": schema.StringAttribute{
+				MarkdownDescription: docstrings.BodyBase64(),
+				Optional:            true,
+				Validators: []validator.String{
+					myvalidator.StringIsBase64(),
+					stringvalidator.ConflictsWith(path.MatchRoot("body")),
+				},
+			},
+
+			"content_type": schema.StringAttribute{
+				MarkdownDescription: docstrings.ContentType(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("body_base64")),
+				},
+			},
+
 			"query_parameters": schema.MapAttribute{
 				ElementType: types.ListType{
 					ElemType: types.StringType,
@@ -135,6 +155,11 @@ func (r *MSGraphResourceAction) Schema(ctx context.Context, req resource.SchemaR
 				ElementType:         types.StringType,
 			},
 
+			"poll_long_running_operation": schema.BoolAttribute{
+				MarkdownDescription: docstrings.PollLongRunningOperation(),
+				Optional:            true,
+			},
+
 			"retry": retry.Schema(ctx),
 
 			"output": schema.DynamicAttribute{
@@ -158,8 +183,12 @@ func (r *MSGraphResourceAction) Configure(ctx context.Context, req resource.Conf
 }
 
 func (r *MSGraphResourceAction) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if planProviderDefaults(ctx, r.client, request, response, false); response.Diagnostics.HasError() {
+		return
+	}
+
 	var plan *MSGraphResourceActionModel
-	if response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
+	if response.Diagnostics.Append(response.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
 		return
 	}
 
@@ -175,7 +204,10 @@ func (r *MSGraphResourceAction) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	createTimeout, diags := model.Timeouts.Create(ctx, 30*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource_action.Create", model.ResourceUrl.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	createTimeout, diags := model.Timeouts.Create(ctx, r.client.Defaults.Timeout("create", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, createTimeout)
 	defer cancel()
@@ -191,7 +223,7 @@ func (r *MSGraphResourceAction) Create(ctx context.Context, req resource.CreateR
 
 	// Execute the action
 	if err := r.executeAction(ctx, model); err != nil {
-		resp.Diagnostics.AddError("Failed to execute action", err.Error())
+		resp.Diagnostics.AddError("Failed to execute action", responseErrorDetail(err))
 		return
 	}
 
@@ -205,14 +237,17 @@ func (r *MSGraphResourceAction) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	createTimeout, diags := model.Timeouts.Create(ctx, 30*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource_action.Update", model.ResourceUrl.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	createTimeout, diags := model.Timeouts.Create(ctx, r.client.Defaults.Timeout("create", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, createTimeout)
 	defer cancel()
 
 	// Re-execute the action
 	if err := r.executeAction(ctx, model); err != nil {
-		resp.Diagnostics.AddError("Failed to execute action", err.Error())
+		resp.Diagnostics.AddError("Failed to execute action", responseErrorDetail(err))
 		return
 	}
 
@@ -228,12 +263,20 @@ func (r *MSGraphResourceAction) executeAction(ctx context.Context, model *MSGrap
 			return fmt.Errorf("failed to unmarshal body: %w", err)
 		}
 	}
+	if !model.BodyBase64.IsNull() {
+		content, err := clients.NewRawContentFromBase64(model.BodyBase64.ValueString(), model.ContentType.ValueString())
+		if err != nil {
+			return fmt.Errorf("failed to decode body_base64: %w", err)
+		}
+		requestBody = content
+	}
 
 	// Prepare request options
 	options := clients.RequestOptions{
-		Headers:         AsMapOfString(model.Headers),
-		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.QueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		Headers:                  AsMapOfString(model.Headers),
+		QueryParameters:          clients.NewQueryParameters(AsMapOfLists(model.QueryParameters)),
+		RetryOptions:             r.client.RetryOptions(model.Retry),
+		PollLongRunningOperation: model.PollLongRunningOperation.ValueBool(),
 	}
 
 	// Construct the full URL from resource_url and action
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -42,6 +43,10 @@ type MSGraphResourceActionDataSourceModel struct {
 	Headers              types.Map         `tfsdk:"headers"`
 	ResponseExportValues map[string]string `tfsdk:"response_export_values"`
 	Retry                retry.Value       `tfsdk:"retry"`
+	MaxItems             types.Int64       `tfsdk:"max_items"`
+	MaxPages             types.Int64       `tfsdk:"max_pages"`
+	PageSize             types.Int64       `tfsdk:"page_size"`
+	Truncated            types.Bool        `tfsdk:"truncated"`
 	Output               types.Dynamic     `tfsdk:"output"`
 	Timeouts             timeouts.Value    `tfsdk:"timeouts"`
 }
@@ -112,8 +117,37 @@ func (r *MSGraphResourceActionDataSource) Schema(ctx context.Context, req dataso
 				ElementType:         types.StringType,
 			},
 
+			"max_items": schema.Int64Attribute{
+				MarkdownDescription: docstrings.MaxItems(),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"max_pages": schema.Int64Attribute{
+				MarkdownDescription: docstrings.ActionMaxPages(),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"page_size": schema.Int64Attribute{
+				MarkdownDescription: docstrings.PageSize(),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
 			"retry": retry.Schema(ctx),
 
+			"truncated": schema.BoolAttribute{
+				MarkdownDescription: docstrings.Truncated(),
+				Computed:            true,
+			},
+
 			"output": schema.DynamicAttribute{
 				MarkdownDescription: docstrings.Output(),
 				Computed:            true,
@@ -140,7 +174,10 @@ func (r *MSGraphResourceActionDataSource) Read(ctx context.Context, req datasour
 		return
 	}
 
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "data.msgraph_resource_action.Read", model.ResourceUrl.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.client.Defaults.Timeout("read", 5*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
@@ -158,7 +195,7 @@ func (r *MSGraphResourceActionDataSource) Read(ctx context.Context, req datasour
 	options := clients.RequestOptions{
 		Headers:         AsMapOfString(model.Headers),
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.QueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
 	}
 
 	// Construct the full URL from resource_url and action
@@ -173,22 +210,39 @@ func (r *MSGraphResourceActionDataSource) Read(ctx context.Context, req datasour
 		method = http.MethodGet
 	}
 
-	// Default to v1.0 API version if not specified
-	apiVersion := model.ApiVersion.ValueString()
-	if apiVersion == "" {
-		apiVersion = "v1.0"
-	}
+	// Default to the provider's default API version if not specified
+	apiVersion := r.client.Defaults.ApiVersionOr(model.ApiVersion.ValueString())
 
 	// Log the action
 	tflog.Info(ctx, fmt.Sprintf("Executing %s action on %s", method, fullUrl))
 
+	// Only the first page is returned, unless the paging is bounded by max_items or max_pages
+	paging := clients.PagingOptions{
+		MaxItems: int(model.MaxItems.ValueInt64()),
+		MaxPages: int(model.MaxPages.ValueInt64()),
+		PageSize: int(model.PageSize.ValueInt64()),
+	}
+	options = paging.Apply(options)
+
 	// Execute the action
 	responseBody, err := r.client.Action(ctx, method, fullUrl, apiVersion, requestBody, options)
 	if err != nil {
-		resp.Diagnostics.AddError("API call failed", err.Error())
+		resp.Diagnostics.AddError("API call failed", responseErrorDetail(err))
 		return
 	}
 
+	truncated := false
+	if paging.MaxItems > 0 || paging.MaxPages > 0 {
+		responseBody, truncated, err = r.client.FollowNextLinks(ctx, responseBody, options, paging)
+		if err != nil {
+			resp.Diagnostics.AddError("API call failed", responseErrorDetail(err))
+			return
+		}
+	} else if responseMap, ok := responseBody.(map[string]interface{}); ok {
+		_, truncated = responseMap["@odata.nextLink"].(string)
+	}
+	model.Truncated = types.BoolValue(truncated)
+
 	// Use the full URL as the ID for this action data source
 	model.Id = types.StringValue(fullUrl)
 
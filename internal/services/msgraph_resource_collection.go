@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,7 +17,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -84,7 +85,6 @@ func (r *MSGraphResourceCollection) Schema(ctx context.Context, req resource.Sch
 				Optional:            true,
 				Computed:            true,
 				Validators:          []validator.String{stringvalidator.OneOf("v1.0", "beta")},
-				Default:             stringdefault.StaticString("v1.0"),
 			},
 
 			"reference_ids": schema.ListAttribute{
@@ -126,8 +126,12 @@ func (r *MSGraphResourceCollection) Configure(ctx context.Context, req resource.
 }
 
 func (r *MSGraphResourceCollection) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if planProviderDefaults(ctx, r.client, request, response, false); response.Diagnostics.HasError() {
+		return
+	}
+
 	var plan, state *MSGraphResourceCollectionModel
-	if response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
+	if response.Diagnostics.Append(response.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
 		return
 	}
 	if response.Diagnostics.Append(request.State.Get(ctx, &state)...); response.Diagnostics.HasError() {
@@ -151,14 +155,17 @@ func (r *MSGraphResourceCollection) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	timeout, diags := model.Timeouts.Create(ctx, 30*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource_collection.Create", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	timeout, diags := model.Timeouts.Create(ctx, r.client.Defaults.Timeout("create", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	newItems := AsListOfString(model.ReferenceIds)
 	if err := r.syncCollection(ctx, model, nil, newItems); err != nil {
-		resp.Diagnostics.AddError("Failed to sync collection", err.Error())
+		resp.Diagnostics.AddError("Failed to sync collection", responseErrorDetail(err))
 		return
 	}
 
@@ -167,11 +174,11 @@ func (r *MSGraphResourceCollection) Create(ctx context.Context, req resource.Cre
 	base := baseCollectionUrl(model.Url.ValueString())
 	opts := clients.RequestOptions{
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
 	}
 	body, err := r.client.List(ctx, base, model.ApiVersion.ValueString(), opts)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read collection", err.Error())
+		resp.Diagnostics.AddError("Failed to read collection", responseErrorDetail(err))
 		return
 	}
 
@@ -184,11 +191,14 @@ func (r *MSGraphResourceCollection) Update(ctx context.Context, req resource.Upd
 	if resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...); resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource_collection.Update", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
 	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
 		return
 	}
 
-	timeout, diags := model.Timeouts.Update(ctx, 30*time.Minute)
+	timeout, diags := model.Timeouts.Update(ctx, r.client.Defaults.Timeout("update", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -196,18 +206,18 @@ func (r *MSGraphResourceCollection) Update(ctx context.Context, req resource.Upd
 	newItems := AsListOfString(model.ReferenceIds)
 	oldItems := AsListOfString(state.ReferenceIds)
 	if err := r.syncCollection(ctx, model, oldItems, newItems); err != nil {
-		resp.Diagnostics.AddError("Failed to sync collection", err.Error())
+		resp.Diagnostics.AddError("Failed to sync collection", responseErrorDetail(err))
 		return
 	}
 
 	base := baseCollectionUrl(model.Url.ValueString())
 	opts := clients.RequestOptions{
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
 	}
 	body, err := r.client.List(ctx, base, model.ApiVersion.ValueString(), opts)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read collection", err.Error())
+		resp.Diagnostics.AddError("Failed to read collection", responseErrorDetail(err))
 		return
 	}
 
@@ -221,7 +231,10 @@ func (r *MSGraphResourceCollection) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	timeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource_collection.Read", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	timeout, diags := model.Timeouts.Read(ctx, r.client.Defaults.Timeout("read", 5*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -229,7 +242,7 @@ func (r *MSGraphResourceCollection) Read(ctx context.Context, req resource.ReadR
 	base := baseCollectionUrl(model.Url.ValueString())
 	opts := clients.RequestOptions{
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
 	}
 	body, err := r.client.List(ctx, base, model.ApiVersion.ValueString(), opts)
 	if err != nil {
@@ -238,7 +251,7 @@ func (r *MSGraphResourceCollection) Read(ctx context.Context, req resource.ReadR
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Failed to read collection", err.Error())
+		resp.Diagnostics.AddError("Failed to read collection", responseErrorDetail(err))
 		return
 	}
 
@@ -258,14 +271,17 @@ func (r *MSGraphResourceCollection) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	timeout, diags := model.Timeouts.Delete(ctx, 30*time.Minute)
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_resource_collection.Delete", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	timeout, diags := model.Timeouts.Delete(ctx, r.client.Defaults.Timeout("delete", 30*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	oldItems := AsListOfString(model.ReferenceIds)
 	if err := r.syncCollection(ctx, model, oldItems, nil); err != nil {
-		resp.Diagnostics.AddError("Failed to sync collection", err.Error())
+		resp.Diagnostics.AddError("Failed to sync collection", responseErrorDetail(err))
 		return
 	}
 }
@@ -295,28 +311,79 @@ func (r *MSGraphResourceCollection) syncCollection(ctx context.Context, model *M
 }
 
 func (r *MSGraphResourceCollection) applyCollection(ctx context.Context, model *MSGraphResourceCollectionModel, toRemove []string, toAdd []string) error {
-	errs := make([]error, 0)
+	apiVersion := model.ApiVersion.ValueString()
+	options := clients.RequestOptions{RetryOptions: r.client.RetryOptions(model.Retry)}
+
+	// the items which can't be added by the bulk form are added by the $ref requests in the batch
+	toAdd = r.bindCollection(ctx, model, toAdd)
+
+	requests := make([]clients.BatchRequest, 0, len(toAdd)+len(toRemove))
 	for _, item := range toAdd {
-		body := map[string]string{}
-		body["@odata.id"] = fmt.Sprintf("%s/%s/directoryObjects/%s", r.client.GraphBaseUrl(), model.ApiVersion.ValueString(), item)
-		_, err := r.client.Create(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), body, clients.RequestOptions{RetryOptions: clients.NewRetryOptions(model.Retry)})
-		if err != nil {
-			errs = append(errs, err)
-		}
+		requests = append(requests, clients.BatchRequest{
+			Method: http.MethodPost,
+			Url:    model.Url.ValueString(),
+			Body: map[string]string{
+				"@odata.id": fmt.Sprintf("%s/%s/directoryObjects/%s", r.client.GraphBaseUrl(), apiVersion, item),
+			},
+		})
 	}
 	for _, item := range toRemove {
-		delUrl := fmt.Sprintf("%s/%s/$ref", baseCollectionUrl(model.Url.ValueString()), item)
-		err := r.client.Delete(ctx, delUrl, model.ApiVersion.ValueString(), clients.RequestOptions{RetryOptions: clients.NewRetryOptions(model.Retry)})
-		if err != nil {
-			errs = append(errs, err)
-		}
+		requests = append(requests, clients.BatchRequest{
+			Method: http.MethodDelete,
+			Url:    fmt.Sprintf("%s/%s/$ref", baseCollectionUrl(model.Url.ValueString()), item),
+		})
 	}
-	if len(errs) > 0 {
-		return fmt.Errorf("errors during sync: %v", errs)
+	if len(requests) == 0 {
+		return nil
+	}
+
+	if _, err := r.client.Batch(ctx, apiVersion, requests, options); err != nil {
+		return fmt.Errorf("errors during sync: %w", err)
 	}
 	return nil
 }
 
+// bindCollection adds the items by PATCH requests with the `{collection}@odata.bind` property to the parent resource,
+// which adds up to 20 items per request, if the collection supports it. It returns the items which are not added.
+func (r *MSGraphResourceCollection) bindCollection(ctx context.Context, model *MSGraphResourceCollectionModel, toAdd []string) []string {
+	parentUrl, property, ok := bulkBindProperty(model.Url.ValueString())
+	if !ok {
+		return toAdd
+	}
+
+	apiVersion := model.ApiVersion.ValueString()
+	options := clients.RequestOptions{RetryOptions: r.client.RetryOptions(model.Retry)}
+	remaining := make([]string, 0)
+	for start := 0; start < len(toAdd); start += clients.MaxBatchSize {
+		end := start + clients.MaxBatchSize
+		if end > len(toAdd) {
+			end = len(toAdd)
+		}
+		references := make([]string, 0, end-start)
+		for _, item := range toAdd[start:end] {
+			references = append(references, fmt.Sprintf("%s/%s/directoryObjects/%s", r.client.GraphBaseUrl(), apiVersion, item))
+		}
+		if _, err := r.client.Update(ctx, parentUrl, apiVersion, map[string]interface{}{property: references}, options); err != nil {
+			// fall back to add the items one by one, so the failed items can be identified
+			tflog.Warn(ctx, fmt.Sprintf("Failed to add %d items by %s, adding them individually: %s", len(references), property, err.Error()))
+			remaining = append(remaining, toAdd[start:end]...)
+		}
+	}
+	return remaining
+}
+
+var bulkBindUrlRegex = regexp.MustCompile(`(?i)^/?(groups/[^/]+)/members/\$ref$`)
+
+// bulkBindProperty returns the URL of the parent resource and the `@odata.bind` property which can be used to add
+// multiple items to the collection in a single PATCH request.
+func bulkBindProperty(collectionUrl string) (string, string, bool) {
+	matches := bulkBindUrlRegex.FindStringSubmatch(collectionUrl)
+	if matches == nil {
+		return "", "", false
+	}
+	return matches[1], "members@odata.bind", true
+}
+
 func flattenReferenceIds(body interface{}) (types.List, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -80,6 +80,29 @@ func TestAcc_ResourceCollectionRetry(t *testing.T) {
 	})
 }
 
+func TestAcc_ResourceCollectionManyMembers(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource_collection", "test")
+	r := MSGraphTestResourceCollection{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			// more members than a single bulk request or batch can hold
+			Config: r.manyMembers(25),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				resource.TestCheckResourceAttr(data.ResourceName, "reference_ids.#", "25"),
+			),
+		},
+		{
+			Config: r.manyMembers(3),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				resource.TestCheckResourceAttr(data.ResourceName, "reference_ids.#", "3"),
+			),
+		},
+	})
+}
+
 func TestAcc_ResourceCollectionReadQueryParameters(t *testing.T) {
 	data := acceptance.BuildTestData(t, "msgraph_resource_collection", "test")
 	r := MSGraphTestResourceCollection{}
@@ -194,6 +217,36 @@ resource "msgraph_resource_collection" "test" {
 `
 }
 
+func (r MSGraphTestResourceCollection) manyMembers(count int) string {
+	return fmt.Sprintf(`
+resource "msgraph_resource" "member" {
+  count = 25
+  url   = "groups"
+  body = {
+    displayName     = "Collection Member ${count.index}"
+    mailEnabled     = false
+    mailNickname    = "collection-member-${count.index}"
+    securityEnabled = true
+  }
+}
+
+resource "msgraph_resource" "group" {
+  url = "groups"
+  body = {
+    displayName     = "Collection Group"
+    mailEnabled     = false
+    mailNickname    = "collection-group"
+    securityEnabled = true
+  }
+}
+
+resource "msgraph_resource_collection" "test" {
+  url           = "groups/${msgraph_resource.group.id}/members/$ref"
+  reference_ids = slice(msgraph_resource.member[*].id, 0, %d)
+}
+`, count)
+}
+
 func (r MSGraphTestResourceCollection) basicWithReadQueryParameters() string {
 	return `
 resource "msgraph_resource" "application_a" {
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+	"github.com/microsoft/terraform-provider-msgraph/internal/docstrings"
+	"github.com/microsoft/terraform-provider-msgraph/internal/retry"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MSGraphResourceDeltaDataSource{}
+
+func NewMSGraphResourceDeltaDataSource() datasource.DataSource {
+	return &MSGraphResourceDeltaDataSource{}
+}
+
+// MSGraphResourceDeltaDataSource defines the data source implementation.
+type MSGraphResourceDeltaDataSource struct {
+	client *clients.MSGraphClient
+}
+
+// MSGraphResourceDeltaDataSourceModel describes the data source data model.
+type MSGraphResourceDeltaDataSourceModel struct {
+	Id                   types.String      `tfsdk:"id"`
+	ApiVersion           types.String      `tfsdk:"api_version"`
+	Url                  types.String      `tfsdk:"url"`
+	DeltaToken           types.String      `tfsdk:"delta_token"`
+	ResponseExportValues map[string]string `tfsdk:"response_export_values"`
+	Headers              types.Map         `tfsdk:"headers"`
+	QueryParameters      types.Map         `tfsdk:"query_parameters"`
+	Retry                retry.Value       `tfsdk:"retry"`
+	NextDeltaToken       types.String      `tfsdk:"next_delta_token"`
+	DeltaLink            types.String      `tfsdk:"delta_link"`
+	Output               types.Dynamic     `tfsdk:"output"`
+	Timeouts             timeouts.Value    `tfsdk:"timeouts"`
+}
+
+func (r *MSGraphResourceDeltaDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_resource_delta"
+}
+
+func (r *MSGraphResourceDeltaDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "This data source can track changes of a collection by using [delta query](https://learn.microsoft.com/en-us/graph/delta-query-overview). " +
+			"Without `delta_token`, it performs an initial round which returns the full state of the collection. With the `next_delta_token` of a previous round as `delta_token`, only the changes since that round are returned. " +
+			"Removed items are returned with the `@removed` property.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The URL of the delta function.",
+				Computed:            true,
+			},
+
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the delta function of a collection, for example `users/delta` or `groups/delta`.",
+				Required:            true,
+			},
+
+			"api_version": schema.StringAttribute{
+				MarkdownDescription: docstrings.ApiVersion(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("v1.0", "beta"),
+				},
+			},
+
+			"delta_token": schema.StringAttribute{
+				MarkdownDescription: "The delta token returned by a previous round in `next_delta_token`. When it's specified, only the changes since that round are returned.",
+				Optional:            true,
+			},
+
+			"response_export_values": schema.MapAttribute{
+				MarkdownDescription: docstrings.ResponseExportValues(),
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+
+			"headers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				MarkdownDescription: "A map of headers to include in the request",
+			},
+
+			"query_parameters": schema.MapAttribute{
+				ElementType: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional:            true,
+				MarkdownDescription: "A map of query parameters to include in the request. The query parameters of the initial round, e.g. `$select` and `$filter`, are encoded in the delta token, so they don't need to be specified again with `delta_token`.",
+			},
+
+			"retry": retry.Schema(ctx),
+
+			"next_delta_token": schema.StringAttribute{
+				MarkdownDescription: "The delta token which can be used as `delta_token` in the next round to fetch the changes since this round.",
+				Computed:            true,
+			},
+
+			"delta_link": schema.StringAttribute{
+				MarkdownDescription: "The `@odata.deltaLink` returned by this round.",
+				Computed:            true,
+			},
+
+			"output": schema.DynamicAttribute{
+				MarkdownDescription: docstrings.Output(),
+				Computed:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Read: true,
+			}),
+		},
+	}
+}
+
+func (r *MSGraphResourceDeltaDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(*clients.Client); ok {
+		r.client = v.MSGraphClient
+	}
+}
+
+func (r *MSGraphResourceDeltaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model MSGraphResourceDeltaDataSourceModel
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &model)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, endSpan := startSpan(ctx, r.client, "data.msgraph_resource_delta.Read", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	readTimeout, diags := model.Timeouts.Read(ctx, r.client.Defaults.Timeout("read", 5*time.Minute))
+	resp.Diagnostics.Append(diags...)
+	ctx, cancelRead := context.WithTimeout(ctx, readTimeout)
+	defer cancelRead()
+
+	apiVersion := r.client.Defaults.ApiVersionOr(model.ApiVersion.ValueString())
+
+	options := clients.RequestOptions{
+		Headers:         AsMapOfString(model.Headers),
+		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.QueryParameters)),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+	result, err := r.client.Delta(ctx, model.Url.ValueString(), apiVersion, model.DeltaToken.ValueString(), options)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to query changes", responseErrorDetail(err))
+		return
+	}
+
+	model.Id = types.StringValue(model.Url.ValueString())
+	model.NextDeltaToken = types.StringValue(result.DeltaToken)
+	model.DeltaLink = types.StringValue(result.DeltaLink)
+	model.Output = types.DynamicValue(buildOutputFromBody(result.Body, model.ResponseExportValues))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
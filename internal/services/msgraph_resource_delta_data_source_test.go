@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/microsoft/terraform-provider-msgraph/internal/acceptance"
+	"github.com/microsoft/terraform-provider-msgraph/internal/acceptance/check"
+)
+
+type MSGraphTestResourceDeltaDataSource struct{}
+
+func TestAcc_DataSourceResourceDeltaBasic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.msgraph_resource_delta", "test")
+	r := MSGraphTestResourceDeltaDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.basic(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("next_delta_token").IsSet(),
+				check.That(data.ResourceName).Key("delta_link").MatchesRegex(regexp.MustCompile(`groups/delta`)),
+				check.That(data.ResourceName).Key("output.%").Exists(),
+			),
+		},
+	})
+}
+
+func TestAcc_DataSourceResourceDeltaWithToken(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.msgraph_resource_delta", "test")
+	r := MSGraphTestResourceDeltaDataSource{}
+
+	data.DataSourceTest(t, []resource.TestStep{
+		{
+			Config: r.withToken(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("next_delta_token").IsSet(),
+				check.That(data.ResourceName).Key("output.%").Exists(),
+			),
+		},
+	})
+}
+
+func (r MSGraphTestResourceDeltaDataSource) basic() string {
+	return `
+data "msgraph_resource_delta" "test" {
+  url = "groups/delta"
+  query_parameters = {
+    "$select" = ["displayName"]
+  }
+  response_export_values = {
+    changes = "value"
+  }
+}`
+}
+
+func (r MSGraphTestResourceDeltaDataSource) withToken() string {
+	return `
+data "msgraph_resource_delta" "initial" {
+  url = "groups/delta"
+  query_parameters = {
+    "$select" = ["displayName"]
+  }
+}
+
+data "msgraph_resource_delta" "test" {
+  url         = "groups/delta"
+  delta_token = data.msgraph_resource_delta.initial.next_delta_token
+  response_export_values = {
+    changes = "value"
+  }
+}`
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+// newTestModifyPlanRequest returns a plan request of a reference, whose api_version is configured if apiVersion is set.
+func newTestModifyPlanRequest(t *testing.T, r *MSGraphResource, apiVersion string, stateApiVersion string) resource.ModifyPlanRequest {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	newState := func(values map[string]string) tfsdk.State {
+		state := tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		}
+		for name, value := range values {
+			if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+		}
+		return state
+	}
+
+	config := map[string]string{"url": "groups/1/members/$ref"}
+	if apiVersion != "" {
+		config["api_version"] = apiVersion
+	}
+	plan := map[string]string{"id": "2", "url": "groups/1/members/$ref", "api_version": stateApiVersion}
+	if apiVersion != "" {
+		plan["api_version"] = apiVersion
+	}
+	req := resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: newState(config).Raw},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: newState(plan).Raw},
+		State:  newState(map[string]string{"id": "2", "url": "groups/1/members/$ref", "api_version": stateApiVersion}),
+	}
+	private := reflect.ValueOf(&req.Private).Elem()
+	private.Set(reflect.New(private.Type().Elem()))
+	return req
+}
+
+func TestMSGraphResource_ModifyPlan_ReferenceApiVersion(t *testing.T) {
+	testcases := []struct {
+		name               string
+		defaultApiVersion  string
+		apiVersion         string
+		wantApiVersion     string
+		wantRequireReplace bool
+	}{
+		{name: "provider default changed", defaultApiVersion: "beta", wantApiVersion: "v1.0", wantRequireReplace: false},
+		{name: "provider default unchanged", defaultApiVersion: "v1.0", wantApiVersion: "v1.0", wantRequireReplace: false},
+		{name: "configured api_version changed", defaultApiVersion: "v1.0", apiVersion: "beta", wantApiVersion: "beta", wantRequireReplace: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestResource(t, http.NotFoundHandler())
+			r.client.Defaults = clients.Defaults{ApiVersion: tc.defaultApiVersion}
+			req := newTestModifyPlanRequest(t, r, tc.apiVersion, "v1.0")
+			// the framework starts the response with the plan of the request
+			resp := resource.ModifyPlanResponse{Plan: req.Plan, Private: req.Private}
+
+			r.ModifyPlan(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			var apiVersion types.String
+			if diags := resp.Plan.GetAttribute(ctx, path.Root("api_version"), &apiVersion); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if apiVersion.ValueString() != tc.wantApiVersion {
+				t.Errorf("api_version: got %q, want %q", apiVersion.ValueString(), tc.wantApiVersion)
+			}
+			if requireReplace := resp.RequiresReplace.Contains(path.Root("api_version")); requireReplace != tc.wantRequireReplace {
+				t.Errorf("requires replace: got %v, want %v", requireReplace, tc.wantRequireReplace)
+			}
+		})
+	}
+}
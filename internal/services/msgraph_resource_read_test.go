@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake"}, nil
+}
+
+// newTestResource returns a msgraph_resource whose requests are served by the handler.
+func newTestResource(t *testing.T, handler http.Handler) *MSGraphResource {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := clients.NewMSGraphClient(fakeCredential{}, &policy.ClientOptions{
+		Cloud: cloud.Configuration{
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				clients.ServiceNameMSGraph: {Endpoint: server.URL, Audience: "https://graph.microsoft.com"},
+			},
+		},
+		Retry: policy.RetryOptions{MaxRetries: -1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &MSGraphResource{client: client}
+}
+
+// newTestReadRequest returns a read request of a group, whose private state marks it as created but not read if createIncomplete is set.
+func newTestReadRequest(t *testing.T, r *MSGraphResource, createIncomplete bool) resource.ReadRequest {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	for name, value := range map[string]string{"id": "00000000-0000-0000-0000-000000000001", "url": "groups", "api_version": "v1.0"} {
+		if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+	}
+
+	req := resource.ReadRequest{State: state}
+	private := reflect.ValueOf(&req.Private).Elem()
+	private.Set(reflect.New(private.Type().Elem()))
+	if createIncomplete {
+		if diags := req.Private.SetKey(ctx, FlagCreateIncomplete, []byte("true")); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+	}
+	return req
+}
+
+func TestMSGraphResource_Read_CreateIncomplete(t *testing.T) {
+	testcases := []struct {
+		name             string
+		createIncomplete bool
+		statusCode       int
+		wantRemoved      bool
+		wantIncomplete   bool
+	}{
+		{name: "read clears the flag", createIncomplete: true, statusCode: http.StatusOK, wantRemoved: false, wantIncomplete: false},
+		{name: "not found after create", createIncomplete: true, statusCode: http.StatusNotFound, wantRemoved: false, wantIncomplete: true},
+		{name: "not found", createIncomplete: false, statusCode: http.StatusNotFound, wantRemoved: true, wantIncomplete: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestResource(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				if tc.statusCode == http.StatusOK {
+					_, _ = w.Write([]byte(`{"id":"00000000-0000-0000-0000-000000000001","displayName":"test"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"error":{"code":"Request_ResourceNotFound","message":"not found"}}`))
+			}))
+			req := newTestReadRequest(t, r, tc.createIncomplete)
+			// the framework starts the response with the state and the private state of the request
+			resp := resource.ReadResponse{State: req.State, Private: req.Private}
+
+			r.Read(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if removed := resp.State.Raw.IsNull(); removed != tc.wantRemoved {
+				t.Errorf("removed: got %v, want %v", removed, tc.wantRemoved)
+			}
+			if incomplete := createIncomplete(ctx, resp.Private); incomplete != tc.wantIncomplete {
+				t.Errorf("create incomplete: got %v, want %v", incomplete, tc.wantIncomplete)
+			}
+		})
+	}
+
+	// once the resource has been read, it's removed from the state when it's not found
+	t.Run("not found after read", func(t *testing.T) {
+		ctx := context.Background()
+		statusCode := http.StatusOK
+		r := newTestResource(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(`{"id":"00000000-0000-0000-0000-000000000001"}`))
+		}))
+		req := newTestReadRequest(t, r, true)
+		resp := resource.ReadResponse{State: req.State, Private: req.Private}
+		r.Read(ctx, req, &resp)
+
+		statusCode = http.StatusNotFound
+		req = resource.ReadRequest{State: resp.State, Private: resp.Private}
+		resp = resource.ReadResponse{State: req.State, Private: req.Private}
+		r.Read(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected error: %v", resp.Diagnostics)
+		}
+		if !resp.State.Raw.IsNull() {
+			t.Errorf("expected the resource to be removed from the state")
+		}
+	})
+}
@@ -153,6 +153,178 @@ func TestAcc_ResourceRetry(t *testing.T) {
 	})
 }
 
+func TestAcc_ResourceRetryBackoff(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withRetryBackoff(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
+	})
+}
+
+func TestAcc_ResourceWaitFor(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withWaitFor(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "wait_for")...),
+	})
+}
+
+func TestAcc_ResourceWaitForDeletion(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withWaitForDeletion(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "wait_for_deletion")...),
+	})
+}
+
+func TestAcc_ResourceDeleteBehaviorPurge(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withDeleteBehaviorPurge(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, append(defaultIgnores(), "delete_behavior", "wait_for_deletion")...),
+	})
+}
+
+func TestAcc_ResourceDeleteBehaviorPurgeInvalidUrl(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:      r.withDeleteBehaviorPurgeInvalidUrl(),
+			ExpectError: regexp.MustCompile(`aren't kept in .directory/deletedItems., so they can't be purged`),
+		},
+	})
+}
+
+func TestAcc_ResourceDeleteBehaviorDetach(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	// the detached extension property is deleted with its application
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withDeleteBehaviorDetach(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That("msgraph_resource.extension").Key("id").IsUUID(),
+			),
+		},
+	})
+}
+
+func TestAcc_ResourceRestoreIfSoftDeleted(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	var id string
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withRestoreIfSoftDeleted(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				resource.TestCheckResourceAttrWith(data.ResourceName, "id", func(value string) error {
+					id = value
+					return nil
+				}),
+			),
+		},
+		{
+			Config:  r.withRestoreIfSoftDeleted(),
+			Destroy: true,
+		},
+		{
+			Config: r.withRestoreIfSoftDeleted(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				resource.TestCheckResourceAttrWith(data.ResourceName, "id", func(value string) error {
+					if value != id {
+						return fmt.Errorf("expected the deleted group %q to be restored, got %q", id, value)
+					}
+					return nil
+				}),
+			),
+		},
+	})
+}
+
+func TestAcc_ResourceSensitiveBody(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withSensitiveBody(data, "SecretP@sswd1"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("sensitive_body").DoesNotExist(),
+			),
+		},
+		{
+			// changing the password updates the user, though the sensitive_body isn't in the state
+			Config: r.withSensitiveBody(data, "SecretP@sswd2"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("sensitive_body").DoesNotExist(),
+			),
+		},
+		data.ImportStepWithImportStateIdFunc(r.ImportIdFunc, defaultIgnores()...),
+	})
+}
+
+func TestAcc_ResourceWaitForDeletionIncludeDeletedItemsWithoutPurge(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config:      r.withWaitForDeletionIncludeDeletedItems(),
+			ExpectError: regexp.MustCompile(`requires .delete_behavior. to be .purge.`),
+		},
+	})
+}
+
 func TestAcc_ResourceTimeouts_Create(t *testing.T) {
 	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
 
@@ -188,6 +360,104 @@ func TestAcc_ResourceTimeouts_Update(t *testing.T) {
 	})
 }
 
+func TestAcc_ResourceETagMode(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withETagMode("strict", "Demo App"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("etag_mode").HasValue("strict"),
+			),
+		},
+		{
+			Config: r.withETagMode("refresh", "Demo App Updated"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("etag_mode").HasValue("refresh"),
+			),
+		},
+	})
+}
+
+func TestAcc_ResourceCreateMethod(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withCreateMethod(data, "Demo App"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+				check.That(data.ResourceName).Key("resource_url").HasValue(fmt.Sprintf("applications(uniqueName='acctest-%s')", data.RandomString)),
+			),
+		},
+		{
+			Config: r.withCreateMethod(data, "Demo App Updated"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("output.display_name").HasValue("Demo App Updated"),
+			),
+		},
+	})
+}
+
+func TestAcc_ResourceOperationUrls(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withOperationUrls(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("resource_url").MatchesRegex(regexp.MustCompile(`^applications/[0-9a-f-]{36}$`)),
+				check.That(data.ResourceName).Key("output.key_id").IsUUID(),
+			),
+		},
+	})
+}
+
+func TestAcc_ResourceUpsertKey(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withUpsertKey(data, false),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+				check.That(data.ResourceName).Key("output.unique_name").HasValue(fmt.Sprintf("acctest-%s", data.RandomString)),
+			),
+		},
+	})
+}
+
+func TestAcc_ResourceAdoptExisting(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
+
+	r := MSGraphTestResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withUpsertKey(data, true),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+				check.That(data.ResourceName).Key("output.unique_name").HasValue(fmt.Sprintf("acctest-%s", data.RandomString)),
+			),
+		},
+	})
+}
+
 func TestAcc_ResourceImport_InvalidIDFormat(t *testing.T) {
 	data := acceptance.BuildTestData(t, "msgraph_resource", "test")
 
@@ -215,7 +485,11 @@ func (r MSGraphTestResource) Exists(ctx context.Context, client *clients.Client,
 	url := state.Attributes["url"]
 
 	var checkUrl string
-	if !strings.Contains(url, "/$ref") {
+	if state.Attributes["read_url"] != "" {
+		checkUrl = state.Attributes["resource_url"]
+	} else if method := state.Attributes["create_method"]; method == "PUT" || method == "PATCH" {
+		checkUrl = strings.TrimSuffix(url, "/$ref")
+	} else if !strings.Contains(url, "/$ref") {
 		checkUrl = fmt.Sprintf("%s/%s", url, state.ID)
 	} else {
 		checkUrl = url
@@ -233,6 +507,67 @@ func (r MSGraphTestResource) Exists(ctx context.Context, client *clients.Client,
 	return nil, fmt.Errorf("checking for presence of existing %s(api_version=%s) resource: %w", state.ID, apiVersion, err)
 }
 
+func (r MSGraphTestResource) withCreateMethod(data acceptance.TestData, displayName string) string {
+	return fmt.Sprintf(`
+resource "msgraph_resource" "test" {
+  url           = "applications(uniqueName='acctest-%[1]s')"
+  create_method = "PATCH"
+  body = {
+    displayName = "%[2]s"
+  }
+  response_export_values = {
+    display_name = "displayName"
+  }
+}
+`, data.RandomString, displayName)
+}
+
+func (r MSGraphTestResource) withOperationUrls() string {
+	return `
+resource "msgraph_resource" "application" {
+  url = "applications"
+  body = {
+    displayName = "Demo App"
+  }
+}
+
+resource "msgraph_resource" "test" {
+  url = "applications/${msgraph_resource.application.id}/addPassword"
+  body = {
+    passwordCredential = {
+      displayName = "Demo Password"
+    }
+  }
+  read_url      = "applications/${msgraph_resource.application.id}"
+  delete_url    = "applications/${msgraph_resource.application.id}/removePassword"
+  delete_method = "POST"
+  delete_body = {
+    keyId = "{response.keyId}"
+  }
+  response_export_values = {
+    key_id = "passwordCredentials[?displayName == 'Demo Password'] | [0].keyId"
+  }
+}
+`
+}
+
+func (r MSGraphTestResource) withUpsertKey(data acceptance.TestData, adoptExisting bool) string {
+	return fmt.Sprintf(`
+resource "msgraph_resource" "test" {
+  url            = "applications"
+  upsert_key     = "uniqueName"
+  adopt_existing = %[2]t
+  body = {
+    uniqueName  = "acctest-%[1]s"
+    displayName = "Demo App"
+  }
+  response_export_values = {
+    unique_name = "uniqueName"
+  }
+}
+`, data.RandomString, adoptExisting)
+}
+
 func (r MSGraphTestResource) ImportIdFunc(tfState *terraform.State) (string, error) {
 	state := tfState.RootModule().Resources["msgraph_resource.test"].Primary
 	url := state.Attributes["url"]
@@ -352,6 +687,167 @@ resource "msgraph_resource" "test" {
 }`
 }
 
+func (r MSGraphTestResource) withRetryBackoff() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "applications"
+  body = {
+    displayName = "Demo App Retry Backoff"
+  }
+  retry = {
+    status_codes          = [404, 409]
+    max_attempts          = 5
+    initial_delay         = "1s"
+    max_delay             = "10s"
+    multiplier            = 1.5
+    response_is_retryable = "error.code == 'Request_ResourceNotFound'"
+  }
+}`
+}
+
+func (r MSGraphTestResource) withWaitFor() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "applications"
+  body = {
+    displayName = "Demo App Wait For"
+  }
+  wait_for = {
+    url        = "applications(appId='{response.appId}')"
+    expression = "appId != null"
+    interval   = "5s"
+    timeout    = "5m"
+  }
+}`
+}
+
+func (r MSGraphTestResource) withWaitForDeletion() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "groups"
+  body = {
+    displayName     = "Demo Group Wait For Deletion"
+    mailEnabled     = false
+    mailNickname    = "demo-group-wait-for-deletion"
+    securityEnabled = true
+  }
+  wait_for_deletion = {
+    interval = "2s"
+  }
+}`
+}
+
+func (r MSGraphTestResource) withDeleteBehaviorPurge() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "groups"
+  body = {
+    displayName     = "Demo Group Purge"
+    mailEnabled     = false
+    mailNickname    = "demo-group-purge"
+    securityEnabled = true
+  }
+  delete_behavior = "purge"
+  wait_for_deletion = {
+    include_deleted_items = true
+  }
+}`
+}
+
+func (r MSGraphTestResource) withDeleteBehaviorPurgeInvalidUrl() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "groups/00000000-0000-0000-0000-000000000000/members/$ref"
+  body = {
+    "@odata.id" = "https://graph.microsoft.com/v1.0/directoryObjects/00000000-0000-0000-0000-000000000000"
+  }
+  delete_behavior = "purge"
+}`
+}
+
+func (r MSGraphTestResource) withDeleteBehaviorDetach() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "applications"
+  body = {
+    displayName = "Demo App Detach"
+  }
+}
+
+resource "msgraph_resource" "extension" {
+  url = "applications/${msgraph_resource.test.id}/extensionProperties"
+  body = {
+    name          = "detached"
+    dataType      = "String"
+    targetObjects = ["User"]
+  }
+  delete_behavior = "detach"
+}`
+}
+
+func (r MSGraphTestResource) withRestoreIfSoftDeleted() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "groups"
+  body = {
+    displayName     = "Demo Group Restore"
+    mailEnabled     = false
+    mailNickname    = "demo-group-restore"
+    securityEnabled = true
+  }
+  restore_if_soft_deleted = true
+}`
+}
+
+func (r MSGraphTestResource) withSensitiveBody(data acceptance.TestData, password string) string {
+	return fmt.Sprintf(`
+data "msgraph_resource" "domains" {
+  url = "domains"
+  response_export_values = {
+    all = "@"
+  }
+}
+
+locals {
+  domain = one([for domain in data.msgraph_resource.domains.output.all.value : domain.id if domain.isInitial])
+}
+
+resource "msgraph_resource" "test" {
+  url = "users"
+  body = {
+    accountEnabled    = true
+    displayName       = "acctest%[1]s"
+    mailNickname      = "acctest%[1]s"
+    userPrincipalName = "acctest%[1]s@${local.domain}"
+    passwordProfile = {
+      forceChangePasswordNextSignIn = false
+    }
+  }
+  sensitive_body = {
+    passwordProfile = {
+      password = "%[2]s"
+    }
+  }
+}
+`, data.RandomString, password)
+}
+
+func (r MSGraphTestResource) withWaitForDeletionIncludeDeletedItems() string {
+	return `
+resource "msgraph_resource" "test" {
+  url = "groups"
+  body = {
+    displayName     = "Demo Group Wait For Deleted Items"
+    mailEnabled     = false
+    mailNickname    = "demo-group-wait-for-deleted-items"
+    securityEnabled = true
+  }
+  wait_for_deletion = {
+    include_deleted_items = true
+  }
+}`
+}
+
 func (r MSGraphTestResource) withCreateTimeout() string {
 	return `
 resource "msgraph_resource" "test" {
@@ -379,3 +875,15 @@ resource "msgraph_resource" "test" {
 }
 `
 }
+
+func (r MSGraphTestResource) withETagMode(etagMode string, displayName string) string {
+	return fmt.Sprintf(`
+resource "msgraph_resource" "test" {
+  url       = "applications"
+  etag_mode = %q
+  body = {
+    displayName = %q
+  }
+}
+`, etagMode, displayName)
+}
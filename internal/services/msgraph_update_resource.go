@@ -4,16 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
@@ -22,6 +22,7 @@ import (
 	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
 	"github.com/microsoft/terraform-provider-msgraph/internal/docstrings"
 	"github.com/microsoft/terraform-provider-msgraph/internal/dynamic"
+	"github.com/microsoft/terraform-provider-msgraph/internal/myvalidator"
 	"github.com/microsoft/terraform-provider-msgraph/internal/retry"
 	"github.com/microsoft/terraform-provider-msgraph/internal/utils"
 )
@@ -48,17 +49,22 @@ func (r *MSGraphUpdateResource) ConfigValidators(ctx context.Context) []resource
 
 // MSGraphUpdateResourceModel describes the resource data model.
 type MSGraphUpdateResourceModel struct {
-	Id                    types.String      `tfsdk:"id"`
-	ApiVersion            types.String      `tfsdk:"api_version"`
-	Url                   types.String      `tfsdk:"url"`
-	Body                  types.Dynamic     `tfsdk:"body"`
-	IgnoreMissingProperty types.Bool        `tfsdk:"ignore_missing_property"`
-	UpdateQueryParameters types.Map         `tfsdk:"update_query_parameters"`
-	ReadQueryParameters   types.Map         `tfsdk:"read_query_parameters"`
-	ResponseExportValues  map[string]string `tfsdk:"response_export_values"`
-	Retry                 retry.Value       `tfsdk:"retry"`
-	Output                types.Dynamic     `tfsdk:"output"`
-	Timeouts              timeouts.Value    `tfsdk:"timeouts"`
+	Id                       types.String      `tfsdk:"id"`
+	ApiVersion               types.String      `tfsdk:"api_version"`
+	Url                      types.String      `tfsdk:"url"`
+	Body                     types.Dynamic     `tfsdk:"body"`
+	BodyBase64               types.String      `tfsdk:"body_base64"`
+	ContentType              types.String      `tfsdk:"content_type"`
+	IgnoreMissingProperty    types.Bool        `tfsdk:"ignore_missing_property"`
+	UpdateQueryParameters    types.Map         `tfsdk:"update_query_parameters"`
+	ReadQueryParameters      types.Map         `tfsdk:"read_query_parameters"`
+	ResponseExportValues     map[string]string `tfsdk:"response_export_values"`
+	Retry                    retry.Value       `tfsdk:"retry"`
+	PollLongRunningOperation types.Bool        `tfsdk:"poll_long_running_operation"`
+	ETagMode                 types.String      `tfsdk:"etag_mode"`
+	WaitFor                  types.Object      `tfsdk:"wait_for"`
+	Output                   types.Dynamic     `tfsdk:"output"`
+	Timeouts                 timeouts.Value    `tfsdk:"timeouts"`
 }
 
 func (r *MSGraphUpdateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -92,7 +98,6 @@ func (r *MSGraphUpdateResource) Schema(ctx context.Context, req resource.SchemaR
 				Validators: []validator.String{
 					stringvalidator.OneOf("v1.0", "beta"),
 				},
-				Default: stringdefault.StaticString("v1.0"),
 			},
 
 			"body": schema.DynamicAttribute{
@@ -100,11 +105,27 @@ func (r *MSGraphUpdateResource) Schema(ctx context.Context, req resource.SchemaR
 				Optional:            true,
 			},
 
+			"body_base64": schema.StringAttribute{
+				MarkdownDescription: docstrings.BodyBase64() + " The content is replaced via a `PUT` request, e.g. to `users/{id}/photo/$value`, and read back to detect the changes made outside of Terraform.",
+				Optional:            true,
+				Validators: []validator.String{
+					myvalidator.StringIsBase64(),
+					stringvalidator.ConflictsWith(path.MatchRoot("body")),
+				},
+			},
+
+			"content_type": schema.StringAttribute{
+				MarkdownDescription: docstrings.ContentType(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("body_base64")),
+				},
+			},
+
 			"ignore_missing_property": schema.BoolAttribute{
 				MarkdownDescription: docstrings.IgnoreMissingProperty(),
 				Optional:            true,
 				Computed:            true,
-				Default:             booldefault.StaticBool(true),
 			},
 
 			"update_query_parameters": schema.MapAttribute{
@@ -120,7 +141,7 @@ func (r *MSGraphUpdateResource) Schema(ctx context.Context, req resource.SchemaR
 					ElemType: types.StringType,
 				},
 				Optional:            true,
-				MarkdownDescription: "A mapping of query parameters to be sent with the read request.",
+				MarkdownDescription: "A mapping of query parameters to be sent with the read request. The `ConsistencyLevel: eventual` header is sent automatically with advanced queries, e.g. `$count=true` or `endsWith` in `$filter`.",
 			},
 
 			"response_export_values": schema.MapAttribute{
@@ -129,8 +150,23 @@ func (r *MSGraphUpdateResource) Schema(ctx context.Context, req resource.SchemaR
 				ElementType:         types.StringType,
 			},
 
+			"poll_long_running_operation": schema.BoolAttribute{
+				MarkdownDescription: docstrings.PollLongRunningOperation(),
+				Optional:            true,
+			},
+
+			"etag_mode": schema.StringAttribute{
+				MarkdownDescription: docstrings.ETagMode(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ETagModes...),
+				},
+			},
+
 			"retry": retry.Schema(ctx),
 
+			"wait_for": waitForSchema("The URL which is polled, e.g. `servicePrincipals/{id}`. Defaults to `url`."),
+
 			"output": schema.DynamicAttribute{
 				MarkdownDescription: docstrings.Output(),
 				Computed:            true,
@@ -155,8 +191,12 @@ func (r *MSGraphUpdateResource) Configure(ctx context.Context, req resource.Conf
 }
 
 func (r *MSGraphUpdateResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if planProviderDefaults(ctx, r.client, request, response, true); response.Diagnostics.HasError() {
+		return
+	}
+
 	var plan *MSGraphUpdateResourceModel
-	if response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
+	if response.Diagnostics.Append(response.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
 		return
 	}
 
@@ -166,7 +206,7 @@ func (r *MSGraphUpdateResource) ModifyPlan(ctx context.Context, request resource
 	}
 }
 
-func (r *MSGraphUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, diagnostics *diag.Diagnostics, isCreate bool) {
+func (r *MSGraphUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State, priorPrivate privateStateGetter, private privateStateSetter, diagnostics *diag.Diagnostics, isCreate bool) {
 	var model MSGraphUpdateResourceModel
 	var stateModel *MSGraphUpdateResourceModel
 	diagnostics.Append(plan.Get(ctx, &model)...)
@@ -175,17 +215,32 @@ func (r *MSGraphUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Pla
 		return
 	}
 
+	spanName := "msgraph_update_resource.Update"
+	if isCreate {
+		spanName = "msgraph_update_resource.Create"
+	}
+	ctx, endSpan := startSpan(ctx, r.client, spanName, model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(diagnostics)
+
 	var writeTimeout time.Duration
 	var diags diag.Diagnostics
 	if isCreate {
-		writeTimeout, diags = model.Timeouts.Create(ctx, 30*time.Minute)
+		writeTimeout, diags = model.Timeouts.Create(ctx, r.client.Defaults.Timeout("create", 30*time.Minute))
 	} else {
-		writeTimeout, diags = model.Timeouts.Update(ctx, 30*time.Minute)
+		writeTimeout, diags = model.Timeouts.Update(ctx, r.client.Defaults.Timeout("update", 30*time.Minute))
 	}
 	diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
 	defer cancel()
 
+	if !model.BodyBase64.IsNull() {
+		r.createUpdateRawContent(ctx, model, state, diagnostics)
+		if !diagnostics.HasError() {
+			r.waitFor(ctx, model, diagnostics)
+		}
+		return
+	}
+
 	data, err := dynamic.ToJSON(model.Body)
 	if err != nil {
 		diagnostics.AddError("Failed to marshal body", err.Error())
@@ -197,36 +252,114 @@ func (r *MSGraphUpdateResource) CreateUpdate(ctx context.Context, plan tfsdk.Pla
 		return
 	}
 
+	readOptions := clients.RequestOptions{
+		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+
+	etagMode := model.ETagMode.ValueString()
+	etag := ""
+	if etagEnabled(etagMode) {
+		if priorPrivate != nil {
+			etag = getETag(ctx, priorPrivate)
+		}
+		// the resource hasn't been read yet, e.g. on create, read it to capture the etag
+		if etag == "" {
+			current, err := r.client.Read(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), readOptions)
+			if err != nil {
+				diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
+				return
+			}
+			etag = etagFromBody(current)
+		}
+	}
+
 	options := clients.RequestOptions{
-		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.UpdateQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		QueryParameters:          clients.NewQueryParameters(AsMapOfLists(model.UpdateQueryParameters)),
+		RetryOptions:             r.client.RetryOptions(model.Retry),
+		PollLongRunningOperation: model.PollLongRunningOperation.ValueBool(),
 	}
-	_, err = r.client.Update(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, options)
+	_, err = r.client.Update(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, withIfMatch(options, etagMode, etag))
+	if err != nil && preconditionFailed(err) && etagMode == ETagModeRefresh {
+		tflog.Info(ctx, fmt.Sprintf("Resource %q was modified after it was last read, re-reading it and retrying the update", model.Url.ValueString()))
+		latest, readErr := r.client.Read(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), readOptions)
+		if readErr != nil {
+			diagnostics.AddError("Failed to read data source", responseErrorDetail(readErr))
+			return
+		}
+		_, err = r.client.Update(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, withIfMatch(options, etagMode, etagFromBody(latest)))
+	}
+	if err != nil {
+		if preconditionFailed(err) {
+			diagnostics.AddError("Failed to update resource", fmt.Sprintf("%s\n\n%s", preconditionFailedDetail, responseErrorDetail(err)))
+			return
+		}
+		diagnostics.AddError("Failed to create resource", responseErrorDetail(err))
+		return
+	}
+
+	responseBody, err := r.client.Read(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), readOptions)
 	if err != nil {
-		diagnostics.AddError("Failed to create resource", err.Error())
+		diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
 		return
 	}
+	diagnostics.Append(setETag(ctx, private, model.ETagMode.ValueString(), responseBody)...)
+	model.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
+	model.Id = types.StringValue(utils.LastSegment(model.Url.ValueString()))
+	diagnostics.Append(state.Set(ctx, &model)...)
 
-	options = clients.RequestOptions{
+	r.waitFor(ctx, model, diagnostics)
+}
+
+// waitFor waits for the condition of wait_for after the properties are updated.
+func (r *MSGraphUpdateResource) waitFor(ctx context.Context, model MSGraphUpdateResourceModel, diagnostics *diag.Diagnostics) {
+	options := clients.RequestOptions{
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
 	}
-	responseBody, err := r.client.Read(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), options)
+	if err := waitFor(ctx, r.client, model.WaitFor, model.Url.ValueString(), nil, model.ApiVersion.ValueString(), options); err != nil {
+		diagnostics.AddError("Failed to wait for the condition of wait_for", responseErrorDetail(err))
+	}
+}
+
+// createUpdateRawContent replaces the binary content of the resource, e.g. a user's photo, with `body_base64`.
+func (r *MSGraphUpdateResource) createUpdateRawContent(ctx context.Context, model MSGraphUpdateResourceModel, state *tfsdk.State, diagnostics *diag.Diagnostics) {
+	content, err := clients.NewRawContentFromBase64(model.BodyBase64.ValueString(), model.ContentType.ValueString())
 	if err != nil {
-		diagnostics.AddError("Failed to read data source", err.Error())
+		diagnostics.AddError("Invalid body_base64", err.Error())
 		return
 	}
-	model.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
+
+	options := clients.RequestOptions{
+		QueryParameters:          clients.NewQueryParameters(AsMapOfLists(model.UpdateQueryParameters)),
+		RetryOptions:             r.client.RetryOptions(model.Retry),
+		PollLongRunningOperation: model.PollLongRunningOperation.ValueBool(),
+	}
+	if _, err := r.client.Action(ctx, http.MethodPut, model.Url.ValueString(), model.ApiVersion.ValueString(), content, options); err != nil {
+		diagnostics.AddError("Failed to update resource", responseErrorDetail(err))
+		return
+	}
+
+	readOptions := clients.RequestOptions{
+		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
+	}
+	remote, err := r.client.ReadRaw(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), readOptions)
+	if err != nil {
+		diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
+		return
+	}
+	model.Output = types.DynamicValue(buildOutputFromBody(remote, model.ResponseExportValues))
 	model.Id = types.StringValue(utils.LastSegment(model.Url.ValueString()))
 	diagnostics.Append(state.Set(ctx, &model)...)
 }
 
 func (r *MSGraphUpdateResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
-	r.CreateUpdate(ctx, request.Plan, &response.State, &response.Diagnostics, true)
+	r.CreateUpdate(ctx, request.Plan, &response.State, nil, response.Private, &response.Diagnostics, true)
 }
 
 func (r *MSGraphUpdateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	r.CreateUpdate(ctx, req.Plan, &resp.State, &resp.Diagnostics, false)
+	r.CreateUpdate(ctx, req.Plan, &resp.State, req.Private, resp.Private, &resp.Diagnostics, false)
 }
 
 func (r *MSGraphUpdateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -235,20 +368,45 @@ func (r *MSGraphUpdateResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_update_resource.Read", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
 	// Apply read timeout (default 5m)
-	readTimeout, diags := model.Timeouts.Read(ctx, 5*time.Minute)
+	readTimeout, diags := model.Timeouts.Read(ctx, r.client.Defaults.Timeout("read", 5*time.Minute))
 	resp.Diagnostics.Append(diags...)
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
 	if model.ApiVersion.ValueString() == "" {
-		model.ApiVersion = types.StringValue("v1.0")
+		model.ApiVersion = types.StringValue(r.client.Defaults.ApiVersionOr(""))
 	}
 
 	options := clients.RequestOptions{
 		QueryParameters: clients.NewQueryParameters(AsMapOfLists(model.ReadQueryParameters)),
-		RetryOptions:    clients.NewRetryOptions(model.Retry),
+		RetryOptions:    r.client.RetryOptions(model.Retry),
 	}
+
+	if !model.BodyBase64.IsNull() {
+		remote, err := r.client.ReadRaw(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), options)
+		if err != nil {
+			if utils.ResponseErrorWasNotFound(err) {
+				tflog.Info(ctx, fmt.Sprintf("Error reading %q - removing from state", model.Id.ValueString()))
+				resp.State.RemoveResource(ctx)
+				return
+			}
+			resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
+			return
+		}
+		state := model
+		state.Output = types.DynamicValue(buildOutputFromBody(remote, model.ResponseExportValues))
+		// the content was changed outside of Terraform
+		if remote.Base64() != model.BodyBase64.ValueString() {
+			state.BodyBase64 = types.StringValue(remote.Base64())
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
 	responseBody, err := r.client.Read(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), options)
 	if err != nil {
 		if utils.ResponseErrorWasNotFound(err) {
@@ -256,10 +414,11 @@ func (r *MSGraphUpdateResource) Read(ctx context.Context, req resource.ReadReque
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Failed to read data source", err.Error())
+		resp.Diagnostics.AddError("Failed to read data source", responseErrorDetail(err))
 		return
 	}
 
+	resp.Diagnostics.Append(setETag(ctx, resp.Private, model.ETagMode.ValueString(), responseBody)...)
 	state := model
 	state.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
 
@@ -107,6 +107,22 @@ func TestAcc_UpdateResourceRetry(t *testing.T) {
 	})
 }
 
+func TestAcc_UpdateResourceWaitFor(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_update_resource", "test")
+
+	r := MSGraphTestUpdateResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.withWaitFor("Demo App Updated"),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("id").IsUUID(),
+			),
+		},
+	})
+}
+
 func TestAcc_UpdateResource_GroupOwnerBind_UpdateDisplayName(t *testing.T) {
 	data := acceptance.BuildTestData(t, "msgraph_update_resource", "test")
 	r := MSGraphTestUpdateResource{}
@@ -127,6 +143,23 @@ func TestAcc_UpdateResource_GroupOwnerBind_UpdateDisplayName(t *testing.T) {
 	})
 }
 
+func TestAcc_UpdateResourceRawContent(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_update_resource", "test")
+	r := MSGraphTestUpdateResource{}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			Config: r.logo(),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Exists(r),
+				check.That(data.ResourceName).Key("output.content_type").HasValue("image/png"),
+				check.That("data.msgraph_resource.logo").Key("content_type").HasValue("image/png"),
+				check.That("data.msgraph_resource.logo").Key("content_sha256").MatchesRegex(regexp.MustCompile(`^[0-9a-f]{64}$`)),
+			),
+		},
+	})
+}
+
 func (r MSGraphTestUpdateResource) Exists(ctx context.Context, client *clients.Client, state *terraform.InstanceState) (*bool, error) {
 	apiVersion := state.Attributes["api_version"]
 	url := state.Attributes["url"]
@@ -165,6 +198,33 @@ resource "msgraph_update_resource" "test" {
 `, displayName)
 }
 
+func (r MSGraphTestUpdateResource) withWaitFor(displayName string) string {
+	return fmt.Sprintf(`
+resource "msgraph_resource" "application" {
+  url = "applications"
+  body = {
+    displayName = "Demo App"
+  }
+
+  lifecycle {
+    ignore_changes = [body.displayName]
+  }
+}
+
+resource "msgraph_update_resource" "test" {
+  url = "applications/${msgraph_resource.application.id}"
+  body = {
+    displayName = "%[1]s"
+  }
+  wait_for = {
+    expression = "displayName == '%[1]s'"
+    interval   = "5s"
+    timeout    = "2m"
+  }
+}
+`, displayName)
+}
+
 func (r MSGraphTestUpdateResource) withUpdateTimeout(displayName string) string {
 	return fmt.Sprintf(`
 %s
@@ -230,6 +290,28 @@ resource "msgraph_resource" "application" {
 `
 }
 
+func (r MSGraphTestUpdateResource) logo() string {
+	return fmt.Sprintf(`
+%s
+
+resource "msgraph_update_resource" "test" {
+  url          = "applications/${msgraph_resource.application.id}/logo"
+  body_base64  = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+  content_type = "image/png"
+  response_export_values = {
+    content_type = "content_type"
+  }
+}
+
+data "msgraph_resource" "logo" {
+  url         = "applications/${msgraph_resource.application.id}/logo"
+  raw_content = true
+
+  depends_on = [msgraph_update_resource.test]
+}
+`, MSGraphTestUpdateResource{}.applicationOnly())
+}
+
 func (r MSGraphTestUpdateResource) withRetry() string {
 	return `
 resource "msgraph_resource" "application" {
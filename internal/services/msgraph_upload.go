@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+	"github.com/microsoft/terraform-provider-msgraph/internal/docstrings"
+	"github.com/microsoft/terraform-provider-msgraph/internal/myvalidator"
+	"github.com/microsoft/terraform-provider-msgraph/internal/retry"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource               = &MSGraphUpload{}
+	_ resource.ResourceWithModifyPlan = &MSGraphUpload{}
+)
+
+func NewMSGraphUpload() resource.Resource {
+	return &MSGraphUpload{}
+}
+
+// MSGraphUpload defines the resource implementation.
+type MSGraphUpload struct {
+	client *clients.MSGraphClient
+}
+
+// MSGraphUploadModel describes the resource data model.
+type MSGraphUploadModel struct {
+	Id                   types.String      `tfsdk:"id"`
+	ApiVersion           types.String      `tfsdk:"api_version"`
+	Url                  types.String      `tfsdk:"url"`
+	Body                 types.Dynamic     `tfsdk:"body"`
+	Source               types.String      `tfsdk:"source"`
+	ChunkSize            types.Int64       `tfsdk:"chunk_size"`
+	ContentSha256        types.String      `tfsdk:"content_sha256"`
+	ResponseExportValues map[string]string `tfsdk:"response_export_values"`
+	Retry                retry.Value       `tfsdk:"retry"`
+	Output               types.Dynamic     `tfsdk:"output"`
+	Timeouts             timeouts.Value    `tfsdk:"timeouts"`
+}
+
+func (r *MSGraphUpload) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_upload"
+}
+
+func (r *MSGraphUpload) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This resource uploads a local file to Microsoft Graph via an upload session, e.g. a file larger than 4 MB to OneDrive or SharePoint, or a large mail attachment. " +
+			"The file is uploaded in chunks, and the upload is resumed when a chunk fails with a transient error. The file is uploaded again when its content changes.\n\n" +
+			"-> **Note** When `msgraph_upload` is deleted, no operation will be performed, and the uploaded item will stay unchanged.",
+		Description: "This resource uploads a local file to Microsoft Graph via an upload session.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the uploaded item, e.g. the drive item or the attachment.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the `createUploadSession` action, for example `drives/{drive-id}/items/{parent-id}:/{file-name}:/createUploadSession` or `users/{user-id}/messages/{message-id}/attachments/createUploadSession`.",
+				Required:            true,
+			},
+
+			"api_version": schema.StringAttribute{
+				MarkdownDescription: docstrings.ApiVersion(),
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("v1.0", "beta"),
+				},
+			},
+
+			"body": schema.DynamicAttribute{
+				MarkdownDescription: "The request body of the `createUploadSession` action, for example `{ item = { \"@microsoft.graph.conflictBehavior\" = \"replace\" } }` for a drive item, or the `AttachmentItem` with the `name` and `size` of the file for a mail attachment.",
+				Optional:            true,
+			},
+
+			"source": schema.StringAttribute{
+				MarkdownDescription: "The path of the local file to upload.",
+				Required:            true,
+			},
+
+			"chunk_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The size in bytes of the chunks uploaded in a request. It must be a multiple of 320 KiB (%d bytes). Defaults to 10 MiB (%d bytes).", clients.UploadChunkSizeUnit, clients.DefaultUploadChunkSize),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(clients.UploadChunkSizeUnit),
+					myvalidator.Int64IsMultipleOf(clients.UploadChunkSizeUnit),
+				},
+			},
+
+			"content_sha256": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded SHA-256 hash of the uploaded file. It's computed when planning, so a change of the file's content triggers a new upload.",
+				Computed:            true,
+			},
+
+			"response_export_values": schema.MapAttribute{
+				MarkdownDescription: docstrings.ResponseExportValues(),
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+
+			"retry": retry.Schema(ctx),
+
+			"output": schema.DynamicAttribute{
+				MarkdownDescription: docstrings.Output(),
+				Computed:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+func (r *MSGraphUpload) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if v, ok := req.ProviderData.(*clients.Client); ok {
+		r.client = v.MSGraphClient
+	}
+}
+
+func (r *MSGraphUpload) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if planProviderDefaults(ctx, r.client, request, response, false); response.Diagnostics.HasError() {
+		return
+	}
+
+	var plan *MSGraphUploadModel
+	if response.Diagnostics.Append(response.Plan.Get(ctx, &plan)...); response.Diagnostics.HasError() {
+		return
+	}
+	// the resource is being destroyed
+	if plan == nil {
+		return
+	}
+
+	var state *MSGraphUploadModel
+	if response.Diagnostics.Append(request.State.Get(ctx, &state)...); response.Diagnostics.HasError() {
+		return
+	}
+
+	// the file may be created during the apply, e.g. by another resource
+	plan.ContentSha256 = types.StringUnknown()
+	if !plan.Source.IsUnknown() {
+		if hash, err := fileSha256(plan.Source.ValueString()); err == nil {
+			plan.ContentSha256 = types.StringValue(hash)
+		} else {
+			tflog.Debug(ctx, fmt.Sprintf("Failed to compute the hash of %q: %v", plan.Source.ValueString(), err))
+		}
+	}
+
+	// the output is only refreshed by a new upload, which may also return another item, e.g. when the url changes
+	if state != nil {
+		if r.requiresUpload(plan, state) {
+			plan.Id = types.StringUnknown()
+			plan.Output = types.DynamicUnknown()
+		} else {
+			plan.Output = state.Output
+		}
+	}
+	response.Diagnostics.Append(response.Plan.Set(ctx, plan)...)
+}
+
+func (r *MSGraphUpload) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *MSGraphUploadModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_upload.Create", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	createTimeout, diags := model.Timeouts.Create(ctx, r.client.Defaults.Timeout("create", 30*time.Minute))
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if err := r.upload(ctx, model); err != nil {
+		resp.Diagnostics.AddError("Failed to upload file", responseErrorDetail(err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *MSGraphUpload) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model *MSGraphUploadModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, endSpan := startSpan(ctx, r.client, "msgraph_upload.Update", model.Url.ValueString(), model.ApiVersion.ValueString())
+	defer endSpan(&resp.Diagnostics)
+
+	var state *MSGraphUploadModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := model.Timeouts.Update(ctx, r.client.Defaults.Timeout("update", 30*time.Minute))
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if r.requiresUpload(model, state) {
+		if err := r.upload(ctx, model); err != nil {
+			resp.Diagnostics.AddError("Failed to upload file", responseErrorDetail(err))
+			return
+		}
+	} else {
+		model.Id = state.Id
+		model.ContentSha256 = state.ContentSha256
+		model.Output = state.Output
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *MSGraphUpload) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *MSGraphUploadModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &model)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The upload session is discarded once the upload is completed, so there's nothing to read,
+	// the changes of the local file are detected by the content_sha256 computed when planning.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *MSGraphUpload) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model *MSGraphUploadModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &model)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Removing the upload %s from state, the uploaded item is kept", model.Id.ValueString()))
+}
+
+// requiresUpload returns whether the file must be uploaded again, the chunk size, retry and timeouts don't affect the uploaded item.
+func (r *MSGraphUpload) requiresUpload(plan *MSGraphUploadModel, state *MSGraphUploadModel) bool {
+	return !plan.Url.Equal(state.Url) ||
+		!plan.ApiVersion.Equal(state.ApiVersion) ||
+		!plan.Body.Equal(state.Body) ||
+		!plan.Source.Equal(state.Source) ||
+		!plan.ContentSha256.Equal(state.ContentSha256) ||
+		!mapOfStringEqual(plan.ResponseExportValues, state.ResponseExportValues)
+}
+
+// upload uploads the source file and sets the computed attributes of the model.
+func (r *MSGraphUpload) upload(ctx context.Context, model *MSGraphUploadModel) error {
+	var requestBody interface{}
+	if err := unmarshalBody(model.Body, &requestBody); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+
+	file, err := os.Open(model.Source.ValueString())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	hash, err := readerSha256(io.NewSectionReader(file, 0, info.Size()))
+	if err != nil {
+		return err
+	}
+
+	options := clients.RequestOptions{
+		RetryOptions: r.client.RetryOptions(model.Retry),
+	}
+	tflog.Info(ctx, fmt.Sprintf("Uploading %s (%d bytes) via %s", model.Source.ValueString(), info.Size(), model.Url.ValueString()))
+	responseBody, err := r.client.Upload(ctx, model.Url.ValueString(), model.ApiVersion.ValueString(), requestBody, file, info.Size(), model.ChunkSize.ValueInt64(), options)
+	if err != nil {
+		return err
+	}
+
+	model.Id = types.StringValue(model.Url.ValueString())
+	if responseMap, ok := responseBody.(map[string]interface{}); ok {
+		if id, ok := responseMap["id"].(string); ok && id != "" {
+			model.Id = types.StringValue(id)
+		}
+	}
+	model.ContentSha256 = types.StringValue(hash)
+	model.Output = types.DynamicValue(buildOutputFromBody(responseBody, model.ResponseExportValues))
+	return nil
+}
+
+func fileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return readerSha256(file)
+}
+
+func readerSha256(reader io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func mapOfStringEqual(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if v, ok := b[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
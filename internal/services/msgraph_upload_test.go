@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/microsoft/terraform-provider-msgraph/internal/acceptance"
+	"github.com/microsoft/terraform-provider-msgraph/internal/acceptance/check"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+type MSGraphTestUpload struct{}
+
+func TestAcc_UploadBasic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "msgraph_upload", "test")
+	r := MSGraphTestUpload{}
+
+	source := filepath.Join(t.TempDir(), "upload.txt")
+	writeFile := func(content string) func() {
+		return func() {
+			if err := os.WriteFile(source, []byte(content), 0o600); err != nil {
+				t.Fatalf("writing %s: %v", source, err)
+			}
+		}
+	}
+
+	data.ResourceTest(t, r, []resource.TestStep{
+		{
+			PreConfig: writeFile(strings.Repeat("a", 1024*1024)),
+			Config:    r.basic(data, source),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("id").Exists(),
+				check.That(data.ResourceName).Key("content_sha256").HasValue("9bc1b2a288b26af7257a36277ae3816a7d4f16e89c1e7e77d0a5c48bad62b360"),
+				check.That(data.ResourceName).Key("output.size").HasValue("1048576"),
+			),
+		},
+		{
+			PreConfig: writeFile(strings.Repeat("b", 512*1024)),
+			Config:    r.basic(data, source),
+			Check: resource.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("output.size").HasValue("524288"),
+			),
+		},
+	})
+}
+
+func (r MSGraphTestUpload) Exists(ctx context.Context, client *clients.Client, state *terraform.InstanceState) (*bool, error) {
+	// the uploaded item is kept when the resource is deleted, it's removed with its parent folder
+	exists := false
+	return &exists, nil
+}
+
+func (r MSGraphTestUpload) basic(data acceptance.TestData, source string) string {
+	return fmt.Sprintf(`
+resource "msgraph_resource" "folder" {
+  url = "sites/root/drive/root/children"
+  body = {
+    name   = "acctest-upload-%[1]s"
+    folder = {}
+  }
+}
+
+resource "msgraph_upload" "test" {
+  url        = "sites/root/drive/items/${msgraph_resource.folder.id}:/upload.txt:/createUploadSession"
+  source     = %[2]q
+  chunk_size = 327680
+  body = {
+    item = {
+      "@microsoft.graph.conflictBehavior" = "replace"
+    }
+  }
+  response_export_values = {
+    size = "size"
+  }
+}
+`, data.RandomString, source)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// FlagSensitiveBodyHash is the private state key which stores the hash of the `sensitive_body` which was last sent.
+// The `sensitive_body` is write-only, so its hash is the only way to detect its changes.
+const FlagSensitiveBodyHash = "sensitive_body_hash"
+
+// sensitiveBody returns the `sensitive_body` of the configuration, it's nil if it's not set or unknown.
+// The write-only attribute is always null in the plan and state, so it's only available in the configuration.
+func sensitiveBody(ctx context.Context, config tfsdk.Config) (interface{}, diag.Diagnostics) {
+	var value types.Dynamic
+	if diags := config.GetAttribute(ctx, path.Root("sensitive_body"), &value); diags.HasError() {
+		return nil, diags
+	}
+	var body interface{}
+	if err := unmarshalBody(value, &body); err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Invalid sensitive_body", fmt.Sprintf(`The argument "sensitive_body" is invalid: %s`, err.Error()))
+		return nil, diags
+	}
+	return body, nil
+}
+
+// sensitiveBodyHash returns the hex-encoded SHA-256 of the JSON of the body, the keys of the objects are sorted by json.Marshal.
+func sensitiveBodyHash(body interface{}) string {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sensitiveBodyChanged returns whether the body differs from the `sensitive_body` which was last sent.
+func sensitiveBodyChanged(ctx context.Context, private privateStateGetter, body interface{}) bool {
+	if body == nil {
+		return false
+	}
+	data, _ := private.GetKey(ctx, FlagSensitiveBodyHash)
+	var hash string
+	if len(data) != 0 {
+		_ = json.Unmarshal(data, &hash)
+	}
+	return hash != sensitiveBodyHash(body)
+}
+
+// setSensitiveBodyHash stores the hash of the body in the private state, whose values must be valid JSON, so it's stored as a JSON string.
+func setSensitiveBodyHash(ctx context.Context, private privateStateSetter, body interface{}) diag.Diagnostics {
+	if body == nil {
+		return nil
+	}
+	data, err := json.Marshal(sensitiveBodyHash(body))
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to store the hash of sensitive_body", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, FlagSensitiveBodyHash, data)
+}
+
+// overlappingProperties returns the paths of the properties which are set in both the body and the sensitive body,
+// the nested objects are compared by their properties, e.g. `passwordProfile.password`.
+func overlappingProperties(body interface{}, sensitiveBody interface{}, prefix string) []string {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	sensitiveMap, ok := sensitiveBody.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	res := make([]string, 0)
+	for key, sensitiveValue := range sensitiveMap {
+		value, ok := bodyMap[key]
+		if !ok {
+			continue
+		}
+		_, isObject := value.(map[string]interface{})
+		_, isSensitiveObject := sensitiveValue.(map[string]interface{})
+		if isObject && isSensitiveObject {
+			res = append(res, overlappingProperties(value, sensitiveValue, prefix+key+".")...)
+		} else {
+			res = append(res, prefix+key)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
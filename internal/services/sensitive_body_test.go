@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSensitiveBodyHash(t *testing.T) {
+	ctx := context.Background()
+	private := newPrivateState()
+	body := map[string]interface{}{"passwordProfile": map[string]interface{}{"password": "secret"}}
+
+	if !sensitiveBodyChanged(ctx, private, body) {
+		t.Errorf("no hash: got unchanged, want changed")
+	}
+	if diags := setSensitiveBodyHash(ctx, private, body); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if sensitiveBodyChanged(ctx, private, body) {
+		t.Errorf("same body: got changed, want unchanged")
+	}
+	rotated := map[string]interface{}{"passwordProfile": map[string]interface{}{"password": "rotated"}}
+	if !sensitiveBodyChanged(ctx, private, rotated) {
+		t.Errorf("rotated body: got unchanged, want changed")
+	}
+	if sensitiveBodyChanged(ctx, private, nil) {
+		t.Errorf("no body: got changed, want unchanged")
+	}
+}
+
+func TestOverlappingProperties(t *testing.T) {
+	body := map[string]interface{}{
+		"displayName":     "user",
+		"passwordProfile": map[string]interface{}{"forceChangePasswordNextSignIn": true, "password": "secret"},
+		"keyCredentials":  []interface{}{},
+	}
+	sensitive := map[string]interface{}{
+		"passwordProfile": map[string]interface{}{"password": "secret"},
+		"keyCredentials":  []interface{}{map[string]interface{}{"key": "key"}},
+	}
+	want := []string{"keyCredentials", "passwordProfile.password"}
+	if got := overlappingProperties(body, sensitive, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+const (
+	DeleteBehaviorDelete = "delete"
+	DeleteBehaviorPurge  = "purge"
+	DeleteBehaviorDetach = "detach"
+)
+
+var DeleteBehaviors = []string{DeleteBehaviorDelete, DeleteBehaviorPurge, DeleteBehaviorDetach}
+
+// deletedItemType is the type of the deleted items of a collection, and the property which identifies a deleted item
+// when `restore_key` isn't set.
+type deletedItemType struct {
+	Type string
+	Key  string
+}
+
+// deletedItemTypes are the collections whose deleted items are kept in `directory/deletedItems`.
+var deletedItemTypes = map[string]deletedItemType{
+	"administrativeUnits": {Type: "microsoft.graph.administrativeUnit", Key: "displayName"},
+	"applications":        {Type: "microsoft.graph.application", Key: "uniqueName"},
+	"groups":              {Type: "microsoft.graph.group", Key: "mailNickname"},
+	"servicePrincipals":   {Type: "microsoft.graph.servicePrincipal", Key: "appId"},
+	"users":               {Type: "microsoft.graph.user", Key: "userPrincipalName"},
+}
+
+// deletedItemCollections describes the keys of deletedItemTypes in the error messages.
+const deletedItemCollections = "`administrativeUnits`, `applications`, `groups`, `servicePrincipals` and `users`"
+
+func deletedItemUrl(id string) string {
+	return fmt.Sprintf("directory/deletedItems/%s", id)
+}
+
+// restoreSoftDeleted restores the deleted item whose key has the same value as the body, and updates it with the body.
+// It returns a body which contains the `id` of the restored resource, or nil if there's no matching deleted item.
+func (r *MSGraphResource) restoreSoftDeleted(ctx context.Context, model *MSGraphResourceModel, requestBody interface{}, options clients.RequestOptions) (interface{}, error) {
+	collection := strings.Trim(model.Url.ValueString(), "/")
+	itemType, ok := deletedItemTypes[collection]
+	if !ok {
+		return nil, fmt.Errorf("the deleted items of %q can't be restored, `restore_if_soft_deleted` only supports %s", collection, deletedItemCollections)
+	}
+	key := itemType.Key
+	if v := model.RestoreKey.ValueString(); v != "" {
+		key = v
+	}
+	value, err := upsertKeyValue(requestBody, key)
+	if err != nil {
+		return nil, fmt.Errorf("the deleted item to restore is identified by %q, set `restore_key` to identify it by another property: %w", key, err)
+	}
+	apiVersion := model.ApiVersion.ValueString()
+
+	deleted, err := r.findByKey(ctx, fmt.Sprintf("directory/deletedItems/%s", itemType.Type), apiVersion, key, value)
+	if err != nil || deleted == nil {
+		return nil, err
+	}
+	id := idFromBody(deleted, "")
+	tflog.Info(ctx, fmt.Sprintf("Restoring the deleted item %q whose %s is %q", id, key, value))
+	if _, err := r.client.Action(ctx, http.MethodPost, deletedItemUrl(id)+"/restore", apiVersion, nil, options); err != nil {
+		return nil, err
+	}
+
+	bodyWithoutKey := make(map[string]interface{})
+	for k, v := range requestBody.(map[string]interface{}) {
+		if k != key {
+			bodyWithoutKey[k] = v
+		}
+	}
+	// the restored resource may not be replicated yet
+	updateOptions := options
+	updateOptions.RetryOptions = clients.CombineRetryOptions(clients.NewRetryOptionsForReadAfterCreate(), options.RetryOptions)
+	if _, err := r.client.Update(ctx, fmt.Sprintf("%s/%s", collection, id), apiVersion, bodyWithoutKey, updateOptions); err != nil {
+		return nil, fmt.Errorf("the deleted item %q was restored, but it couldn't be updated: %w", id, err)
+	}
+	return deleted, nil
+}
+
+// purge permanently deletes the deleted item of the resource. The deleted item may not be replicated right after the
+// deletion, so `404 Not Found` is retried until the context is done.
+func (r *MSGraphResource) purge(ctx context.Context, model *MSGraphResourceModel, options clients.RequestOptions) error {
+	id := model.Id.ValueString()
+	if id == "" || strings.Contains(id, "/") {
+		return fmt.Errorf("the resource %q isn't a directory object which is addressed by its id, so it can't be purged from `directory/deletedItems`", model.ResourceUrl.ValueString())
+	}
+	options.RetryOptions = clients.CombineRetryOptions(clients.NewRetryOptionsForReadAfterCreate(), options.RetryOptions)
+	return r.client.Delete(ctx, deletedItemUrl(id), model.ApiVersion.ValueString(), options)
+}
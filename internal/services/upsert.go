@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+)
+
+const (
+	preferHeader          = "Prefer"
+	preferCreateIfMissing = "create-if-missing"
+)
+
+// upsertKeyValue returns the value of the upsert key in the body, e.g. the `uniqueName` of an application.
+func upsertKeyValue(body interface{}, key string) (string, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("the body must be an object which contains the upsert key %q", key)
+	}
+	value, ok := bodyMap[key].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("the body doesn't contain a string value of the upsert key %q", key)
+	}
+	return value, nil
+}
+
+// odataString returns the value as an OData string literal, single quotes are escaped by doubling them.
+func odataString(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
+
+// alternateKeyUrl returns the URL which addresses the resource by the alternate key, e.g. `applications(uniqueName='app')`.
+func alternateKeyUrl(url string, key string, value string) string {
+	return fmt.Sprintf("%s(%s=%s)", strings.TrimSuffix(url, "/"), key, odataString(value))
+}
+
+// upsert creates the resource identified by the upsert key, or adopts the existing one. It returns a body which contains the `id` of the resource.
+//
+// With `adopt_existing`, the collection is queried by the key first, and an existing resource is updated with the body instead of
+// creating another one. Otherwise, the resource is upserted via `PATCH {url}({key}='{value}')` with `Prefer: create-if-missing`.
+func (r *MSGraphResource) upsert(ctx context.Context, model *MSGraphResourceModel, requestBody interface{}, options clients.RequestOptions) (interface{}, error) {
+	key := model.UpsertKey.ValueString()
+	value, err := upsertKeyValue(requestBody, key)
+	if err != nil {
+		return nil, err
+	}
+	apiVersion := model.ApiVersion.ValueString()
+
+	// the key of an existing resource is not changed, and an alternate key is part of the URL, so it isn't sent in the body
+	bodyWithoutKey := make(map[string]interface{})
+	for k, v := range requestBody.(map[string]interface{}) {
+		if k != key {
+			bodyWithoutKey[k] = v
+		}
+	}
+
+	if model.AdoptExisting.ValueBool() {
+		existing, err := r.findByKey(ctx, model.Url.ValueString(), apiVersion, key, value)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return r.client.Create(ctx, model.Url.ValueString(), apiVersion, requestBody, options)
+		}
+		tflog.Info(ctx, fmt.Sprintf("Adopting the existing resource %q whose %s is %q", existing["id"], key, value))
+		if _, err := r.client.Update(ctx, fmt.Sprintf("%s/%s", model.Url.ValueString(), existing["id"]), apiVersion, bodyWithoutKey, options); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	headers := make(map[string]string, len(options.Headers)+1)
+	for k, v := range options.Headers {
+		headers[k] = v
+	}
+	headers[preferHeader] = preferCreateIfMissing
+	options.Headers = headers
+
+	itemUrl := alternateKeyUrl(model.Url.ValueString(), key, value)
+	responseBody, err := r.client.Action(ctx, http.MethodPatch, itemUrl, apiVersion, bodyWithoutKey, options)
+	if err != nil {
+		return nil, err
+	}
+	if idFromBody(responseBody, "") != "" {
+		return responseBody, nil
+	}
+	// an existing resource is updated with `204 No Content`, so its id is read
+	return r.client.Read(ctx, itemUrl, apiVersion, clients.NewRequestOptions(nil, map[string][]string{"$select": {"id"}}))
+}
+
+// findByKey returns the resource in the collection whose key has the value, or nil if there's none.
+func (r *MSGraphResource) findByKey(ctx context.Context, url string, apiVersion string, key string, value string) (map[string]interface{}, error) {
+	options := clients.NewRequestOptions(nil, map[string][]string{
+		"$filter": {fmt.Sprintf("%s eq %s", key, odataString(value))},
+		"$select": {"id"},
+	})
+	responseBody, err := r.client.List(ctx, url, apiVersion, options)
+	if err != nil {
+		return nil, err
+	}
+	responseMap, _ := responseBody.(map[string]interface{})
+	items, _ := responseMap["value"].([]interface{})
+	switch len(items) {
+	case 0:
+		return nil, nil
+	case 1:
+		item, ok := items[0].(map[string]interface{})
+		if !ok || idFromBody(item, "") == "" {
+			return nil, fmt.Errorf("the resource whose %s is %q doesn't have an `id`", key, value)
+		}
+		return item, nil
+	default:
+		return nil, fmt.Errorf("there are %d resources whose %s is %q in %s, the upsert key must identify a single resource", len(items), key, value, url)
+	}
+}
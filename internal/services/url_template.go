@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/microsoft/terraform-provider-msgraph/internal/utils"
+)
+
+// FlagTemplateValues is the private state key which stores the values of the create response referenced by the URL templates.
+const FlagTemplateValues = "template_values"
+
+const responsePlaceholderPrefix = "response."
+
+// templatePlaceholderRegex matches the `{id}` and `{response.<path>}` placeholders, e.g. `{response.keyId}`.
+var templatePlaceholderRegex = regexp.MustCompile(`\{(id|response\.[^{}]+)\}`)
+
+// urlTemplates returns the templates of the resource, i.e. the per-operation URLs, the URL of wait_for and the string values of the delete body.
+func urlTemplates(model *MSGraphResourceModel) []string {
+	templates := make([]string, 0)
+	urls := []types.String{model.ReadUrl, model.UpdateUrl, model.DeleteUrl}
+	if !model.WaitFor.IsNull() && !model.WaitFor.IsUnknown() {
+		if v, ok := model.WaitFor.Attributes()["url"].(types.String); ok {
+			urls = append(urls, v)
+		}
+	}
+	for _, v := range urls {
+		if !v.IsNull() && !v.IsUnknown() {
+			templates = append(templates, v.ValueString())
+		}
+	}
+	var deleteBody interface{}
+	if err := unmarshalBody(model.DeleteBody, &deleteBody); err == nil {
+		templates = append(templates, stringValues(deleteBody)...)
+	}
+	return templates
+}
+
+// templateValues returns the values of the `{response.<path>}` placeholders in the templates, the paths are JMESPath
+// queries evaluated against the create response.
+func templateValues(templates []string, responseBody interface{}) map[string]string {
+	values := make(map[string]string)
+	for _, template := range templates {
+		for _, match := range templatePlaceholderRegex.FindAllStringSubmatch(template, -1) {
+			path, ok := strings.CutPrefix(match[1], responsePlaceholderPrefix)
+			if !ok {
+				continue
+			}
+			result, ok := utils.ExtractObjectJMES(responseBody, "value", path).(map[string]interface{})
+			if !ok || result["value"] == nil {
+				continue
+			}
+			if v, ok := result["value"].(string); ok {
+				values[path] = v
+			} else {
+				values[path] = fmt.Sprintf("%v", result["value"])
+			}
+		}
+	}
+	return values
+}
+
+// expandTemplate replaces the placeholders in the template with the id and the values captured from the create response.
+func expandTemplate(template string, id string, values map[string]string) (string, error) {
+	var err error
+	out := templatePlaceholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := strings.Trim(placeholder, "{}")
+		if name == "id" {
+			if id == "" {
+				err = fmt.Errorf("the placeholder %s in %q can't be resolved, because the resource doesn't have an id", placeholder, template)
+			}
+			return id
+		}
+		value, ok := values[strings.TrimPrefix(name, responsePlaceholderPrefix)]
+		if !ok {
+			err = fmt.Errorf("the placeholder %s in %q can't be resolved, the values of the create response are only captured when the resource is created, "+
+				"so the placeholders can't be added afterwards, e.g. to an imported resource", placeholder, template)
+		}
+		return value
+	})
+	return out, err
+}
+
+// expandBody replaces the placeholders in the string values of the body.
+func expandBody(body interface{}, id string, values map[string]string) (interface{}, error) {
+	switch v := body.(type) {
+	case string:
+		return expandTemplate(v, id, values)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			expanded, err := expandBody(value, id, values)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			expanded, err := expandBody(value, id, values)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, expanded)
+		}
+		return out, nil
+	default:
+		return body, nil
+	}
+}
+
+func stringValues(body interface{}) []string {
+	switch v := body.(type) {
+	case string:
+		return []string{v}
+	case map[string]interface{}:
+		out := make([]string, 0)
+		for _, value := range v {
+			out = append(out, stringValues(value)...)
+		}
+		return out
+	case []interface{}:
+		out := make([]string, 0)
+		for _, value := range v {
+			out = append(out, stringValues(value)...)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// operationUrl returns the expanded template of an operation, or the URL of the resource instance if there's no template.
+func operationUrl(template types.String, model *MSGraphResourceModel, values map[string]string) (string, error) {
+	if template.IsNull() || template.IsUnknown() || template.ValueString() == "" {
+		return resourceItemUrl(model), nil
+	}
+	return expandTemplate(template.ValueString(), model.Id.ValueString(), values)
+}
+
+func setTemplateValues(ctx context.Context, private privateStateSetter, values map[string]string) diag.Diagnostics {
+	if len(values) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to marshal the template values", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, FlagTemplateValues, data)
+}
+
+func getTemplateValues(ctx context.Context, private privateStateGetter) map[string]string {
+	values := make(map[string]string)
+	if data, _ := private.GetKey(ctx, FlagTemplateValues); len(data) != 0 {
+		_ = json.Unmarshal(data, &values)
+	}
+	return values
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/microsoft/terraform-provider-msgraph/internal/clients"
+	"github.com/microsoft/terraform-provider-msgraph/internal/myvalidator"
+	"github.com/microsoft/terraform-provider-msgraph/internal/utils"
+)
+
+const (
+	defaultWaitForInterval = 10 * time.Second
+	defaultWaitForTimeout  = 10 * time.Minute
+)
+
+type WaitForModel struct {
+	Expression types.String `tfsdk:"expression"`
+	Url        types.String `tfsdk:"url"`
+	Interval   types.String `tfsdk:"interval"`
+	Timeout    types.String `tfsdk:"timeout"`
+}
+
+func waitForAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"expression": types.StringType,
+		"url":        types.StringType,
+		"interval":   types.StringType,
+		"timeout":    types.StringType,
+	}
+}
+
+func waitForSchema(urlDescription string) schema.Attribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		MarkdownDescription: "A condition which is polled after the resource is created or updated, until it's met, so resources which depend on it don't race with the eventual consistency of Microsoft Graph, " +
+			"e.g. a service principal which isn't usable yet, or the `mail` of a group which is populated later.",
+		Attributes: map[string]schema.Attribute{
+			"expression": schema.StringAttribute{
+				Required: true,
+				MarkdownDescription: "A [JMESPath](https://jmespath.org/) expression which is evaluated against the response, the condition is met if the result is truthy, e.g. `mail != null`. " +
+					"`false`, `null`, and empty strings, arrays and objects are falsy.",
+				Validators: []validator.String{
+					myvalidator.StringIsValidJMESPath(),
+				},
+			},
+			"url": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: urlDescription,
+			},
+			"interval": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The interval between the polls, e.g. `5s`. Defaults to `10s`.",
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+			},
+			"timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The maximum time to wait for the condition, e.g. `5m`. It's also bounded by the timeout of the operation. Defaults to `10m`.",
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+			},
+		},
+	}
+}
+
+// waitFor polls the URL until the expression of the wait_for object is met, the URL is replaced by the URL of the object if it's set,
+// which is expanded by expandUrl unless it's nil. A 404 response is treated as the condition not being met, because the resource
+// may not be replicated yet.
+func waitFor(ctx context.Context, client *clients.MSGraphClient, value types.Object, url string, expandUrl func(string) (string, error), apiVersion string, options clients.RequestOptions) error {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	var model WaitForModel
+	if diags := value.As(ctx, &model, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return fmt.Errorf("reading wait_for: %v", diags)
+	}
+	if v := model.Url.ValueString(); v != "" {
+		url = v
+		if expandUrl != nil {
+			var err error
+			if url, err = expandUrl(v); err != nil {
+				return err
+			}
+		}
+	}
+	interval := parseDurationOr(model.Interval, defaultWaitForInterval)
+	timeout := parseDurationOr(model.Timeout, defaultWaitForTimeout)
+	expression := model.Expression.ValueString()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		responseBody, err := client.Read(ctx, url, apiVersion, options)
+		switch {
+		case err == nil:
+			met, err := utils.EvaluateJMESCondition(responseBody, expression)
+			if err != nil {
+				return fmt.Errorf("evaluating %q: %w", expression, err)
+			}
+			if met {
+				return nil
+			}
+			tflog.Debug(ctx, fmt.Sprintf("Waiting for %q of %q, polling again in %s", expression, url, interval))
+		case utils.ResponseErrorWasNotFound(err):
+			tflog.Debug(ctx, fmt.Sprintf("Waiting for %q to exist, polling again in %s", url, interval))
+		case ctx.Err() != nil:
+			return fmt.Errorf("the condition %q of %q wasn't met within %s: %w", expression, url, timeout, err)
+		default:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the condition %q of %q wasn't met within %s", expression, url, timeout)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func parseDurationOr(value types.String, fallback time.Duration) time.Duration {
+	if value.IsNull() || value.IsUnknown() {
+		return fallback
+	}
+	d, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+const defaultWaitForDeletionInterval = 5 * time.Second
+
+type WaitForDeletionModel struct {
+	IncludeDeletedItems types.Bool   `tfsdk:"include_deleted_items"`
+	Interval            types.String `tfsdk:"interval"`
+}
+
+func waitForDeletionAttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"include_deleted_items": types.BoolType,
+		"interval":              types.StringType,
+	}
+}
+
+func waitForDeletionSchema() schema.Attribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		MarkdownDescription: "If set, the deletion waits until the resource can't be read anymore, so a replacement with the same unique properties, e.g. `uniqueName` or `mailNickname`, doesn't conflict with it. " +
+			"The resource is polled at the URL it's read at until it returns `404 Not Found`, the wait is bounded by the delete timeout.",
+		Attributes: map[string]schema.Attribute{
+			"include_deleted_items": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to also wait until the resource disappears from `directory/deletedItems`, i.e. until it's permanently deleted. " +
+					"A deleted directory object is kept in `directory/deletedItems` for 30 days, so it requires `delete_behavior` to be `purge`. Defaults to `false`.",
+			},
+			"interval": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The interval between the polls, e.g. `10s`. Defaults to `5s`.",
+				Validators: []validator.String{
+					myvalidator.StringIsDuration(),
+				},
+			},
+		},
+	}
+}
+
+// waitForDeletion polls the URL until it returns 404, and if include_deleted_items is set, the deleted item of the ID as well.
+// The wait is bounded by the context, i.e. the delete timeout.
+func waitForDeletion(ctx context.Context, client *clients.MSGraphClient, value types.Object, url string, id string, apiVersion string, options clients.RequestOptions) error {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	var model WaitForDeletionModel
+	if diags := value.As(ctx, &model, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return fmt.Errorf("reading wait_for_deletion: %v", diags)
+	}
+	urls := []string{url}
+	// the deleted items are directory objects, which are addressed by their ID
+	if model.IncludeDeletedItems.ValueBool() && id != "" && !strings.Contains(id, "/") {
+		urls = append(urls, deletedItemUrl(id))
+	}
+	interval := parseDurationOr(model.Interval, defaultWaitForDeletionInterval)
+
+	for _, url := range urls {
+		for {
+			_, err := client.Read(ctx, url, apiVersion, options)
+			if utils.ResponseErrorWasNotFound(err) {
+				break
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("%q wasn't deleted before the timeout: %w", url, err)
+				}
+				return err
+			}
+			tflog.Debug(ctx, fmt.Sprintf("Waiting for %q to be deleted, polling again in %s", url, interval))
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%q wasn't deleted before the timeout", url)
+			case <-time.After(interval):
+			}
+		}
+	}
+	return nil
+}
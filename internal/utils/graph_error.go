@@ -0,0 +1,250 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// GraphError is the error envelope returned by Microsoft Graph, together with the request which caused it.
+type GraphError struct {
+	Method          string
+	Url             string
+	StatusCode      int
+	Code            string
+	Message         string
+	RequestId       string
+	ClientRequestId string
+	Date            string
+	Details         []GraphErrorDetail
+}
+
+type GraphErrorDetail struct {
+	Code    string
+	Message string
+	Target  string
+}
+
+type graphErrorEnvelope struct {
+	Error struct {
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+		InnerError struct {
+			RequestId       string `json:"request-id"`
+			ClientRequestId string `json:"client-request-id"`
+			Date            string `json:"date"`
+		} `json:"innerError"`
+		Details []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Target  string `json:"target"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// ParseGraphError returns the Graph error of the response error wrapped in err, it returns false if err doesn't wrap a response error.
+// The request IDs are sourced from the `innerError` object, or the response headers if it's missing.
+func ParseGraphError(err error) (*GraphError, bool) {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) {
+		return nil, false
+	}
+
+	out := &GraphError{
+		StatusCode: responseErr.StatusCode,
+		Code:       responseErr.ErrorCode,
+	}
+	resp := responseErr.RawResponse
+	if resp == nil {
+		return out, true
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		out.Method = resp.Request.Method
+		u := *resp.Request.URL
+		u.RawQuery = ""
+		out.Url = u.String()
+	}
+
+	if body, err := runtime.Payload(resp); err == nil && len(body) > 0 {
+		var envelope graphErrorEnvelope
+		if err := json.Unmarshal(body, &envelope); err == nil {
+			if envelope.Error.Code != "" {
+				out.Code = envelope.Error.Code
+			}
+			out.Message = envelope.Error.Message
+			out.RequestId = envelope.Error.InnerError.RequestId
+			out.ClientRequestId = envelope.Error.InnerError.ClientRequestId
+			out.Date = envelope.Error.InnerError.Date
+			for _, detail := range envelope.Error.Details {
+				out.Details = append(out.Details, GraphErrorDetail{
+					Code:    detail.Code,
+					Message: detail.Message,
+					Target:  detail.Target,
+				})
+			}
+		} else {
+			out.Message = strings.TrimSpace(string(body))
+		}
+	}
+
+	if out.RequestId == "" {
+		out.RequestId = resp.Header.Get("request-id")
+	}
+	if out.ClientRequestId == "" {
+		out.ClientRequestId = resp.Header.Get("client-request-id")
+	}
+	if out.Date == "" {
+		out.Date = resp.Header.Get("Date")
+	}
+	return out, true
+}
+
+// Summary returns a one-line summary of the error, e.g. `403 Forbidden: Authorization_RequestDenied: Insufficient privileges to complete the operation.`
+func (e *GraphError) Summary() string {
+	parts := []string{strings.TrimSpace(fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)))}
+	if e.Code != "" {
+		parts = append(parts, e.Code)
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// String returns the summary of the error, followed by the request, the nested details, the IDs to share with the
+// Microsoft support, and a remediation hint for the well-known error codes.
+func (e *GraphError) String() string {
+	msg := &strings.Builder{}
+	msg.WriteString(e.Summary())
+	msg.WriteString("\n")
+
+	if e.Url != "" {
+		fmt.Fprintf(msg, "\nRequest: %s %s", e.Method, e.Url)
+	}
+	for _, detail := range e.Details {
+		line := strings.Trim(fmt.Sprintf("%s: %s", detail.Code, detail.Message), ": ")
+		if detail.Target != "" {
+			line = fmt.Sprintf("%s (target: %s)", line, detail.Target)
+		}
+		fmt.Fprintf(msg, "\nDetail: %s", line)
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(msg, "\nRequest ID: %s", e.RequestId)
+	}
+	if e.ClientRequestId != "" {
+		fmt.Fprintf(msg, "\nClient request ID: %s", e.ClientRequestId)
+	}
+	if e.Date != "" {
+		fmt.Fprintf(msg, "\nDate: %s", e.Date)
+	}
+
+	if hint := e.Hint(); hint != "" {
+		fmt.Fprintf(msg, "\n\nHint: %s", hint)
+	}
+	return strings.TrimRight(msg.String(), "\n")
+}
+
+// Hint returns an actionable hint for the well-known error codes and status codes, or an empty string.
+func (e *GraphError) Hint() string {
+	switch e.Code {
+	case "Authorization_RequestDenied", "Authorization_IdentityNotFound", "accessDenied", "Forbidden", "AccessDenied":
+		return e.permissionHint()
+	case "InvalidAuthenticationToken", "Authentication_ExpiredToken", "Authentication_MissingOrMalformed", "Authentication_Unauthorized", "CompactToken_ParsingFailed":
+		return authenticationHint
+	case "Request_ResourceNotFound", "ResourceNotFound", "itemNotFound", "ErrorItemNotFound", "Directory_ObjectNotFound":
+		return notFoundHint
+	case "ObjectConflict", "Request_MultipleObjectsWithSameKeyValue", "nameAlreadyExists", "conflict", "Conflict":
+		return conflictHint
+	case "Request_UnsupportedQuery", "Request_BadRequest", "BadRequest", "invalidRequest":
+		if strings.Contains(strings.ToLower(e.Message), "consistencylevel") || strings.Contains(e.Message, "$count") || e.Code == "Request_UnsupportedQuery" {
+			return advancedQueryHint
+		}
+		return badRequestHint
+	case "Directory_QuotaExceeded", "quotaLimitReached":
+		return quotaHint
+	case "TooManyRequests", "activityLimitReached", "throttledRequest":
+		return throttlingHint
+	}
+
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return authenticationHint
+	case http.StatusForbidden:
+		return e.permissionHint()
+	case http.StatusNotFound:
+		return notFoundHint
+	case http.StatusConflict:
+		return conflictHint
+	case http.StatusTooManyRequests:
+		return throttlingHint
+	}
+	return ""
+}
+
+const (
+	authenticationHint = "The access token was rejected. Check the credentials and the `tenant_id` configured in the provider, and that the token is issued for the Microsoft Graph endpoint of the configured `environment`."
+	notFoundHint       = "The resource doesn't exist, or it was created recently and hasn't been replicated yet. If it was just created, configure the `retry` block to retry on `error_message_regex` matching the error."
+	conflictHint       = "An object with the same unique properties already exists. Import the existing object, or change the unique properties, e.g. `displayName`, `mailNickname` or `uniqueName`."
+	badRequestHint     = "Check the request body and query parameters against the Microsoft Graph API reference, and that the properties are supported by the configured `api_version`, some are only available in `beta`."
+	advancedQueryHint  = "This is likely an advanced query, which requires the `ConsistencyLevel: eventual` header and the `$count=true` query parameter."
+	quotaHint          = "The directory object quota of the tenant or of the identity used by the provider is exceeded. Delete unused objects or request a higher quota."
+	throttlingHint     = "The requests are throttled by Microsoft Graph. Reduce the number of concurrent requests via the `max_concurrent_requests` provider attribute."
+)
+
+// permissionsByCollection maps the first segment of a URL to the Microsoft Graph permissions which are usually required
+// to read and to write it.
+var permissionsByCollection = map[string][2]string{
+	"applications":           {"Application.Read.All", "Application.ReadWrite.All"},
+	"serviceprincipals":      {"Application.Read.All", "Application.ReadWrite.All"},
+	"users":                  {"User.Read.All", "User.ReadWrite.All"},
+	"groups":                 {"Group.Read.All", "Group.ReadWrite.All"},
+	"devices":                {"Device.Read.All", "Device.ReadWrite.All"},
+	"administrativeunits":    {"AdministrativeUnit.Read.All", "AdministrativeUnit.ReadWrite.All"},
+	"directoryroles":         {"RoleManagement.Read.Directory", "RoleManagement.ReadWrite.Directory"},
+	"rolemanagement":         {"RoleManagement.Read.Directory", "RoleManagement.ReadWrite.Directory"},
+	"oauth2permissiongrants": {"DelegatedPermissionGrant.Read.All", "DelegatedPermissionGrant.ReadWrite.All"},
+	"policies":               {"Policy.Read.All", "Policy.ReadWrite.ApplicationConfiguration"},
+	"identity":               {"Policy.Read.All", "Policy.ReadWrite.ConditionalAccess"},
+	"identitygovernance":     {"EntitlementManagement.Read.All", "EntitlementManagement.ReadWrite.All"},
+	"domains":                {"Domain.Read.All", "Domain.ReadWrite.All"},
+	"organization":           {"Organization.Read.All", "Organization.ReadWrite.All"},
+	"contacts":               {"OrgContact.Read.All", "OrgContact.Read.All"},
+	"sites":                  {"Sites.Read.All", "Sites.ReadWrite.All"},
+	"teams":                  {"Team.ReadBasic.All", "TeamSettings.ReadWrite.All"},
+}
+
+func (e *GraphError) permissionHint() string {
+	hint := "The identity used by the provider doesn't have the permission for this operation."
+	if permissions, ok := permissionsByCollection[strings.ToLower(collectionOf(e.Url))]; ok {
+		permission := permissions[1]
+		if e.Method == http.MethodGet {
+			permission = permissions[0]
+		}
+		hint += fmt.Sprintf(" It likely requires the `%s` Microsoft Graph permission, or a directory role which grants it.", permission)
+	} else {
+		hint += " Check the permissions listed in the Microsoft Graph API reference of the operation."
+	}
+	return hint + " Newly granted permissions may take a few minutes to take effect."
+}
+
+// collectionOf returns the first segment after the API version of the URL, e.g. `groups` of `https://graph.microsoft.com/v1.0/groups/{id}/members`.
+func collectionOf(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) > 0 && (segments[0] == "v1.0" || segments[0] == "beta") {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return segments[0]
+}
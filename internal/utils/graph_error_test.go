@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+func newGraphResponseError(method string, url string, statusCode int, header http.Header, body string) error {
+	req, _ := http.NewRequest(method, url, nil)
+	if header == nil {
+		header = http.Header{}
+	}
+	return runtime.NewResponseError(&http.Response{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		Request:    req,
+	})
+}
+
+func TestParseGraphError(t *testing.T) {
+	testcases := []struct {
+		name     string
+		err      error
+		ok       bool
+		expected GraphError
+		contains []string
+	}{
+		{
+			name: "not a response error",
+			err:  errors.New("some error"),
+			ok:   false,
+		},
+		{
+			name: "permission denied",
+			err: newGraphResponseError(http.MethodPost, "https://graph.microsoft.com/v1.0/applications?$select=id", http.StatusForbidden, nil,
+				`{"error":{"code":"Authorization_RequestDenied","message":"Insufficient privileges to complete the operation.","innerError":{"date":"2024-01-01T00:00:00","request-id":"req-1","client-request-id":"client-1"}}}`),
+			ok: true,
+			expected: GraphError{
+				Method:          http.MethodPost,
+				Url:             "https://graph.microsoft.com/v1.0/applications",
+				StatusCode:      http.StatusForbidden,
+				Code:            "Authorization_RequestDenied",
+				Message:         "Insufficient privileges to complete the operation.",
+				RequestId:       "req-1",
+				ClientRequestId: "client-1",
+				Date:            "2024-01-01T00:00:00",
+			},
+			contains: []string{
+				"403 Forbidden: Authorization_RequestDenied: Insufficient privileges to complete the operation.",
+				"Request: POST https://graph.microsoft.com/v1.0/applications",
+				"Request ID: req-1",
+				"Client request ID: client-1",
+				"`Application.ReadWrite.All`",
+			},
+		},
+		{
+			name: "permission denied on read",
+			err: newGraphResponseError(http.MethodGet, "https://graph.microsoft.com/beta/groups/1/members", http.StatusForbidden, nil,
+				`{"error":{"code":"Authorization_RequestDenied","message":"Insufficient privileges to complete the operation."}}`),
+			ok:       true,
+			contains: []string{"`Group.Read.All`"},
+		},
+		{
+			name: "nested details and request ids from headers",
+			err: newGraphResponseError(http.MethodPost, "https://graph.microsoft.com/v1.0/groups", http.StatusBadRequest, http.Header{"Request-Id": []string{"req-2"}, "Client-Request-Id": []string{"client-2"}},
+				`{"error":{"code":"Request_BadRequest","message":"Invalid value specified for property.","details":[{"code":"InvalidValue","message":"mailNickname is invalid","target":"mailNickname"}]}}`),
+			ok: true,
+			expected: GraphError{
+				Method:          http.MethodPost,
+				Url:             "https://graph.microsoft.com/v1.0/groups",
+				StatusCode:      http.StatusBadRequest,
+				Code:            "Request_BadRequest",
+				Message:         "Invalid value specified for property.",
+				RequestId:       "req-2",
+				ClientRequestId: "client-2",
+				Details:         []GraphErrorDetail{{Code: "InvalidValue", Message: "mailNickname is invalid", Target: "mailNickname"}},
+			},
+			contains: []string{
+				"Detail: InvalidValue: mailNickname is invalid (target: mailNickname)",
+				"api_version",
+			},
+		},
+		{
+			name:     "not found",
+			err:      newGraphResponseError(http.MethodGet, "https://graph.microsoft.com/v1.0/users/1", http.StatusNotFound, nil, `{"error":{"code":"Request_ResourceNotFound","message":"Resource '1' does not exist."}}`),
+			ok:       true,
+			contains: []string{"`retry`"},
+		},
+		{
+			name:     "conflict",
+			err:      newGraphResponseError(http.MethodPost, "https://graph.microsoft.com/v1.0/groups", http.StatusBadRequest, nil, `{"error":{"code":"Request_MultipleObjectsWithSameKeyValue","message":"Another object with the same value for property uniqueName already exists."}}`),
+			ok:       true,
+			contains: []string{"Import the existing object"},
+		},
+		{
+			name:     "advanced query",
+			err:      newGraphResponseError(http.MethodGet, "https://graph.microsoft.com/v1.0/users", http.StatusBadRequest, nil, `{"error":{"code":"Request_UnsupportedQuery","message":"Unsupported Query."}}`),
+			ok:       true,
+			contains: []string{"ConsistencyLevel: eventual"},
+		},
+		{
+			name: "body which is not json",
+			err:  newGraphResponseError(http.MethodGet, "https://graph.microsoft.com/v1.0/users", http.StatusBadGateway, nil, `Bad Gateway`),
+			ok:   true,
+			expected: GraphError{
+				Method:     http.MethodGet,
+				Url:        "https://graph.microsoft.com/v1.0/users",
+				StatusCode: http.StatusBadGateway,
+				Message:    "Bad Gateway",
+			},
+		},
+		{
+			name: "wrapped response error",
+			err: fmt.Errorf("errors during sync: %w", newGraphResponseError(http.MethodGet, "https://graph.microsoft.com/v1.0/users", http.StatusUnauthorized, nil,
+				`{"error":{"code":"InvalidAuthenticationToken","message":"Access token has expired or is not yet valid."}}`)),
+			ok:       true,
+			contains: []string{"401 Unauthorized: InvalidAuthenticationToken", "`tenant_id`"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := ParseGraphError(tc.err)
+			if ok != tc.ok {
+				t.Fatalf("expected ok to be %v, got %v", tc.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if tc.expected.StatusCode != 0 && fmt.Sprintf("%+v", *actual) != fmt.Sprintf("%+v", tc.expected) {
+				t.Fatalf("expected %+v, got %+v", tc.expected, *actual)
+			}
+			for _, v := range tc.contains {
+				if !strings.Contains(actual.String(), v) {
+					t.Fatalf("expected %q to contain %q", actual.String(), v)
+				}
+			}
+		})
+	}
+}
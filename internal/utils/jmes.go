@@ -14,3 +14,30 @@ func ExtractObjectJMES(old interface{}, pathKey, path string) interface{} {
 	result[pathKey] = value
 	return result
 }
+
+// EvaluateJMESCondition evaluates the JMESPath expression against the input, and returns whether the result is truthy.
+// Like JMESPath, false, null, and empty strings, arrays and objects are false, other values are true.
+func EvaluateJMESCondition(input interface{}, expression string) (bool, error) {
+	value, err := jmes.Search(expression, input)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(value), nil
+}
+
+func isTruthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) != 0
+	case map[string]interface{}:
+		return len(v) != 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,41 @@
+package utils
+
+import "testing"
+
+func TestEvaluateJMESCondition(t *testing.T) {
+	input := map[string]interface{}{
+		"mail":           "group@contoso.com",
+		"proxyAddresses": []interface{}{},
+		"accountEnabled": false,
+		"error": map[string]interface{}{
+			"code": "Request_ResourceNotFound",
+		},
+	}
+	testcases := []struct {
+		expression string
+		want       bool
+		wantErr    bool
+	}{
+		{expression: "mail", want: true},
+		{expression: "mail != null", want: true},
+		{expression: "displayName", want: false},
+		{expression: "proxyAddresses", want: false},
+		{expression: "accountEnabled", want: false},
+		{expression: "error", want: true},
+		{expression: "error.code == 'Request_ResourceNotFound'", want: true},
+		{expression: "error.code == 'Authorization_RequestDenied'", want: false},
+		{expression: "length(proxyAddresses) > `0`", want: false},
+		{expression: "mail ==", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		got, err := EvaluateJMESCondition(input, tc.expression)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("EvaluateJMESCondition(%q): got error %v, want error %v", tc.expression, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("EvaluateJMESCondition(%q): got %v, want %v", tc.expression, got, tc.want)
+		}
+	}
+}
@@ -69,23 +69,62 @@ type UpdateJsonOption struct {
 	IgnoreCasing          bool
 	IgnoreMissingProperty bool
 	IgnoreNullProperty    bool
+	// WriteOnly is an object whose leaves are the write-only properties, e.g. the `sensitive_body`. They're never returned by the API,
+	// so UpdateObject drops them from the result, and DiffObject leaves them out of the patch.
+	WriteOnly interface{}
+}
+
+// property returns the option of the value of the property, and whether the property is write-only.
+func (option UpdateJsonOption) property(key string) (UpdateJsonOption, bool) {
+	writeOnlyMap, ok := option.WriteOnly.(map[string]interface{})
+	if !ok {
+		return option, false
+	}
+	value, ok := writeOnlyMap[key]
+	if !ok {
+		option.WriteOnly = nil
+		return option, false
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return option, true
+	}
+	option.WriteOnly = value
+	return option, false
+}
+
+// withoutWriteOnly returns the value without the write-only properties.
+func withoutWriteOnly(value interface{}, writeOnly interface{}) interface{} {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok || writeOnly == nil {
+		return value
+	}
+	res := make(map[string]interface{}, len(valueMap))
+	for key, v := range valueMap {
+		propertyOption, isWriteOnly := UpdateJsonOption{WriteOnly: writeOnly}.property(key)
+		if !isWriteOnly {
+			res[key] = withoutWriteOnly(v, propertyOption.WriteOnly)
+		}
+	}
+	return res
 }
 
 // UpdateObject is used to get an updated object which has same schema as old, but with new value
 func UpdateObject(old interface{}, new interface{}, option UpdateJsonOption) interface{} {
 	if reflect.DeepEqual(old, new) {
-		return old
+		return withoutWriteOnly(old, option.WriteOnly)
 	}
 	switch oldValue := old.(type) {
 	case map[string]interface{}:
 		if newMap, ok := new.(map[string]interface{}); ok {
 			res := make(map[string]interface{})
 			for key, value := range oldValue {
+				propertyOption, writeOnly := option.property(key)
 				switch {
+				case writeOnly:
 				case value == nil && option.IgnoreNullProperty:
 					res[key] = nil
 				case newMap[key] != nil:
-					res[key] = UpdateObject(value, newMap[key], option)
+					res[key] = UpdateObject(value, newMap[key], propertyOption)
 				case option.IgnoreMissingProperty || isZeroValue(value):
 					res[key] = value
 				}
@@ -215,13 +254,17 @@ func DiffObject(old interface{}, new interface{}, option UpdateJsonOption) inter
 			res := make(map[string]interface{})
 			// include keys present in new
 			for key, newVal := range newMap {
+				propertyOption, writeOnly := option.property(key)
+				if writeOnly {
+					continue
+				}
 				if oldVal, ok := oldValue[key]; ok {
-					if d := DiffObject(oldVal, newVal, option); !IsEmptyObject(d) {
+					if d := DiffObject(oldVal, newVal, propertyOption); !IsEmptyObject(d) {
 						res[key] = d
 					}
 				} else {
 					// key doesn't exist in old -> create
-					res[key] = newVal
+					res[key] = withoutWriteOnly(newVal, propertyOption.WriteOnly)
 				}
 			}
 			if len(res) == 0 {
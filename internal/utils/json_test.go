@@ -102,6 +102,20 @@ func TestUpdateObject(t *testing.T) {
 			opt:  UpdateJsonOption{IgnoreCasing: true},
 			want: "Hello",
 		},
+		{
+			name: "write-only properties dropped",
+			old:  map[string]interface{}{"a": 1, "p": map[string]interface{}{"force": true, "password": "secret"}},
+			newV: map[string]interface{}{"a": 10, "p": map[string]interface{}{"force": true}},
+			opt:  UpdateJsonOption{IgnoreMissingProperty: true, WriteOnly: map[string]interface{}{"p": map[string]interface{}{"password": "secret"}}},
+			want: map[string]interface{}{"a": 10, "p": map[string]interface{}{"force": true}},
+		},
+		{
+			name: "write-only properties dropped from unchanged object",
+			old:  map[string]interface{}{"a": 1, "secret": "value"},
+			newV: map[string]interface{}{"a": 1, "secret": "value"},
+			opt:  UpdateJsonOption{WriteOnly: map[string]interface{}{"secret": "value"}},
+			want: map[string]interface{}{"a": 1},
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -142,6 +156,20 @@ func TestDiffObject(t *testing.T) {
 			opt:  UpdateJsonOption{},
 			want: nil,
 		},
+		{
+			name: "write-only properties left out",
+			old:  map[string]interface{}{"a": 1, "p": map[string]interface{}{"force": true}},
+			newV: map[string]interface{}{"a": 2, "p": map[string]interface{}{"force": true, "password": "secret"}, "keys": []interface{}{"key"}},
+			opt:  UpdateJsonOption{WriteOnly: map[string]interface{}{"p": map[string]interface{}{"password": "secret"}, "keys": []interface{}{"key"}}},
+			want: map[string]interface{}{"a": 2},
+		},
+		{
+			name: "write-only properties left out of new object",
+			old:  map[string]interface{}{"a": 1},
+			newV: map[string]interface{}{"a": 1, "p": map[string]interface{}{"force": true, "password": "secret"}},
+			opt:  UpdateJsonOption{WriteOnly: map[string]interface{}{"p": map[string]interface{}{"password": "secret"}}},
+			want: map[string]interface{}{"p": map[string]interface{}{"force": true}},
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
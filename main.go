@@ -5,6 +5,7 @@ import (
 	"flag"
 	"log"
 
+	tfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/microsoft/terraform-provider-msgraph/internal/provider"
 )
@@ -26,7 +27,10 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(), opts)
+	// the provider instance outlives the server, so the throttling summary of the whole run is logged once it stops
+	p := &provider.MSGraphProvider{}
+	err := providerserver.Serve(context.Background(), func() tfprovider.Provider { return p }, opts)
+	p.Close()
 	if err != nil {
 		log.Fatal(err.Error())
 	}